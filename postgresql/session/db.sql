@@ -0,0 +1,10 @@
+CREATE TABLE userSession (
+    session_key VARCHAR(64),
+    username VARCHAR(256) NOT NULL,
+    data BYTEA NOT NULL,
+    expires_at timestamptz NOT NULL,
+    PRIMARY KEY(session_key)
+);
+
+CREATE INDEX userSession_username ON userSession (username);
+CREATE INDEX userSession_expires_at ON userSession (expires_at);
@@ -0,0 +1,179 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	sessions "github.com/christapa/testContainers/session"
+)
+
+// SqlSessionRepository : Sessions stored in the userSession table, under the hash of their ID
+// Expired rows are ignored by every query, and deleted by DeleteExpired or the sweeper
+type SqlSessionRepository struct {
+	db  *sql.DB
+	TTL time.Duration
+	// Codec of the saved sessions, sessions.JSONCodec by default
+	Codec sessions.Codec
+	// Sealer signs or encrypts the saved sessions, nil to store them as they are
+	Sealer sessions.Sealer
+}
+
+var _ sessions.SessionRepository = (*SqlSessionRepository)(nil)
+
+func NewSqlSessionRepository(db *sql.DB, ttl time.Duration) *SqlSessionRepository {
+	return &SqlSessionRepository{
+		db:    db,
+		TTL:   ttl,
+		Codec: sessions.JSONCodec,
+	}
+}
+
+// Save : The expiry is computed by the database, so the clocks of the instances do not matter
+func (s *SqlSessionRepository) Save(ctx context.Context, session *sessions.Session) error {
+	key := sessions.HashID(session.SessionID)
+
+	data, err := sessions.Encode(s.Codec, s.Sealer, key, session)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO userSession (session_key, username, data, expires_at)
+		VALUES ($1, $2, $3, now() + $4::double precision * interval '1 millisecond')
+		ON CONFLICT (session_key) DO UPDATE
+		SET username = EXCLUDED.username, data = EXCLUDED.data, expires_at = EXCLUDED.expires_at`,
+		key,
+		session.Username,
+		data,
+		s.TTL.Milliseconds(),
+	)
+	return err
+}
+
+func (s *SqlSessionRepository) Get(ctx context.Context, sessionID string) (*sessions.Session, error) {
+	key := sessions.HashID(sessionID)
+
+	row := s.db.QueryRowContext(ctx,
+		`SELECT data FROM userSession WHERE session_key = $1 AND expires_at > now()`,
+		key,
+	)
+
+	var data []byte
+	err := row.Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, sessions.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return sessions.Decode(s.Codec, s.Sealer, key, data)
+}
+
+func (s *SqlSessionRepository) Delete(ctx context.Context, sessionID string) error {
+	_, err := s.db.ExecContext(ctx,
+		`DELETE FROM userSession WHERE session_key = $1`,
+		sessions.HashID(sessionID),
+	)
+	return err
+}
+
+func (s *SqlSessionRepository) Touch(ctx context.Context, sessionID string) error {
+	result, err := s.db.ExecContext(ctx,
+		`UPDATE userSession SET expires_at = now() + $2::double precision * interval '1 millisecond'
+		WHERE session_key = $1 AND expires_at > now()`,
+		sessions.HashID(sessionID),
+		s.TTL.Milliseconds(),
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sessions.ErrNotFound
+	}
+
+	return nil
+}
+
+// ListByUsername : Sessions that are not sealed by the keyring are skipped
+func (s *SqlSessionRepository) ListByUsername(ctx context.Context, username string) ([]*sessions.Session, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT session_key, data FROM userSession WHERE username = $1 AND expires_at > now()`,
+		username,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	listed := make([]*sessions.Session, 0)
+	for rows.Next() {
+		var key string
+		var data []byte
+		if err := rows.Scan(&key, &data); err != nil {
+			return nil, err
+		}
+
+		session, err := sessions.Decode(s.Codec, s.Sealer, key, data)
+		if errors.Is(err, sessions.ErrInvalidSeal) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		listed = append(listed, session)
+	}
+
+	return listed, rows.Err()
+}
+
+// RevokeAllForUser : Expired rows of the user are deleted too, but not counted
+func (s *SqlSessionRepository) RevokeAllForUser(ctx context.Context, username string) (int64, error) {
+	row := s.db.QueryRowContext(ctx,
+		`WITH deleted AS (DELETE FROM userSession WHERE username = $1 RETURNING expires_at)
+		SELECT count(*) FROM deleted WHERE expires_at > now()`,
+		username,
+	)
+
+	var revoked int64
+	err := row.Scan(&revoked)
+	return revoked, err
+}
+
+// DeleteExpired : Returns the number of rows deleted
+func (s *SqlSessionRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	result, err := s.db.ExecContext(ctx,
+		`DELETE FROM userSession WHERE expires_at <= now()`,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// RunSweeper : Call DeleteExpired every interval until ctx is done, errors are logged
+func (s *SqlSessionRepository) RunSweeper(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.DeleteExpired(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("failed to delete expired sessions: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/christapa/testContainers/postgresql/user"
+	sessions "github.com/christapa/testContainers/session"
+	"github.com/christapa/testContainers/session/sessiontest"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	testcontainers "github.com/testcontainers/testcontainers-go"
+	postgresmodules "github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+func newPostgresConnectionToTestContainer(t *testing.T) *sql.DB {
+	t.Helper()
+	ctx := context.Background()
+
+	postgresContainer, err := postgresmodules.RunContainer(ctx,
+		testcontainers.WithImage("docker.io/postgres"),
+		postgresmodules.WithInitScripts(filepath.Join("db.sql")),
+		postgresmodules.WithDatabase("example"),
+		postgresmodules.WithUsername("postgres"),
+		postgresmodules.WithPassword("postgres"),
+		testcontainers.WithWaitStrategy(
+			wait.ForLog("database system is ready to accept connections").
+				WithOccurrence(2).
+				WithStartupTimeout(5*time.Second)),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = postgresContainer.Terminate(ctx) })
+
+	connectionString, err := postgresContainer.ConnectionString(ctx, "sslmode=disable")
+	require.NoError(t, err)
+
+	conn, err := user.NewConn(connectionString)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return conn
+}
+
+func TestSqlSessionRepository_Contract(t *testing.T) {
+	databaseConnection := newPostgresConnectionToTestContainer(t)
+
+	sessiontest.Run(t, func(t *testing.T, ttl time.Duration) sessions.SessionRepository {
+		return NewSqlSessionRepository(databaseConnection, ttl)
+	})
+}
+
+func TestSqlSessionRepository_Sweeper(t *testing.T) {
+	ctx := context.Background()
+	databaseConnection := newPostgresConnectionToTestContainer(t)
+
+	shortRepository := NewSqlSessionRepository(databaseConnection, 100*time.Millisecond)
+	longRepository := NewSqlSessionRepository(databaseConnection, 10*time.Minute)
+
+	for _, repository := range []*SqlSessionRepository{shortRepository, shortRepository, longRepository} {
+		err := repository.Save(ctx, &sessions.Session{
+			SessionID: uuid.New().String(),
+			Username:  "test",
+		})
+		require.NoError(t, err)
+	}
+
+	sweeperCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		longRepository.RunSweeper(sweeperCtx, 100*time.Millisecond)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool {
+		var count int
+		err := databaseConnection.QueryRowContext(ctx, `SELECT count(*) FROM userSession`).Scan(&count)
+		return err == nil && count == 1
+	}, 5*time.Second, 100*time.Millisecond)
+
+	cancel()
+	<-done
+
+	deleted, err := longRepository.DeleteExpired(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), deleted)
+}
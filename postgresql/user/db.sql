@@ -1,6 +1,6 @@
 CREATE TABLE userAuthentication (
     email VARCHAR(256),
-    password_hash VARCHAR(60),
+    password_hash VARCHAR(255),
     last_login timestamp,
     PRIMARY KEY(email)
 );
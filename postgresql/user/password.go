@@ -0,0 +1,184 @@
+package user
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	AlgorithmArgon2id = "argon2id"
+	AlgorithmBcrypt   = "bcrypt"
+)
+
+var ErrUnknownHash = errors.New("unknown password hash format")
+
+// Highest argon2id parameters accepted from a stored hash, a tampered hash must not exhaust the memory
+const (
+	maxArgon2Memory     = 1024 * 1024
+	maxArgon2Iterations = 10
+)
+
+// Argon2Params : Memory is in KiB
+type Argon2Params struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultArgon2Params : Second recommended option of RFC 9106, with 64 MiB of memory
+var DefaultArgon2Params = Argon2Params{
+	Memory:      64 * 1024,
+	Iterations:  3,
+	Parallelism: 4,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+// PasswordHasher : Hash new passwords with one algorithm, and verify the hashes of both
+// The parameters are stored in the hash, so hashes made with other parameters are still verified
+type PasswordHasher struct {
+	algorithm  string
+	argon2     Argon2Params
+	bcryptCost int
+
+	// AllowLegacyPlaintext verifies the hashes not starting with $ as the plaintext passwords stored before hashing
+	// Off by default, they are rejected with ErrUnknownHash
+	AllowLegacyPlaintext bool
+}
+
+func NewArgon2idHasher(params Argon2Params) *PasswordHasher {
+	return &PasswordHasher{
+		algorithm: AlgorithmArgon2id,
+		argon2:    params,
+	}
+}
+
+func NewBcryptHasher(cost int) *PasswordHasher {
+	return &PasswordHasher{
+		algorithm:  AlgorithmBcrypt,
+		bcryptCost: cost,
+	}
+}
+
+// Hash : argon2id hashes use the PHC string format, $argon2id$v=19$m=65536,t=3,p=4$salt$key
+func (h *PasswordHasher) Hash(password string) (string, error) {
+	if h.algorithm == AlgorithmBcrypt {
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), h.bcryptCost)
+		return string(hash), err
+	}
+
+	salt := make([]byte, h.argon2.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.argon2.Iterations, h.argon2.Memory, h.argon2.Parallelism, h.argon2.KeyLength)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version,
+		h.argon2.Memory,
+		h.argon2.Iterations,
+		h.argon2.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Verify : rehash is true when the password matches a hash of another algorithm or with other parameters
+// See AllowLegacyPlaintext for the plaintext passwords stored before hashing, they always need a rehash
+func (h *PasswordHasher) Verify(password string, hash string) (match bool, rehash bool, err error) {
+	switch {
+	case strings.HasPrefix(hash, "$argon2id$"):
+		params, salt, key, err := decodeArgon2id(hash)
+		if err != nil {
+			return false, false, err
+		}
+
+		computed := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, params.KeyLength)
+		if subtle.ConstantTimeCompare(computed, key) != 1 {
+			return false, false, nil
+		}
+
+		return true, h.algorithm != AlgorithmArgon2id || params != h.argon2, nil
+
+	case strings.HasPrefix(hash, "$2a$"), strings.HasPrefix(hash, "$2b$"), strings.HasPrefix(hash, "$2y$"):
+		err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, false, nil
+		}
+		if err != nil {
+			return false, false, err
+		}
+
+		cost, err := bcrypt.Cost([]byte(hash))
+		if err != nil {
+			return false, false, err
+		}
+
+		return true, h.algorithm != AlgorithmBcrypt || cost != h.bcryptCost, nil
+
+	case h.AllowLegacyPlaintext && hash != "" && !strings.HasPrefix(hash, "$"):
+		// Compared through their SHA-256 so the time does not depend on the length either
+		computed, stored := sha256.Sum256([]byte(password)), sha256.Sum256([]byte(hash))
+		if subtle.ConstantTimeCompare(computed[:], stored[:]) != 1 {
+			return false, false, nil
+		}
+
+		return true, true, nil
+
+	default:
+		return false, false, ErrUnknownHash
+	}
+}
+
+func decodeArgon2id(hash string) (Argon2Params, []byte, []byte, error) {
+	// "", "argon2id", "v=19", "m=65536,t=3,p=4", salt, key
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 {
+		return Argon2Params{}, nil, nil, ErrUnknownHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return Argon2Params{}, nil, nil, ErrUnknownHash
+	}
+	if version != argon2.Version {
+		return Argon2Params{}, nil, nil, fmt.Errorf("unsupported argon2 version %d", version)
+	}
+
+	var params Argon2Params
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return Argon2Params{}, nil, nil, ErrUnknownHash
+	}
+	// argon2 panics on them
+	if params.Iterations == 0 || params.Parallelism == 0 {
+		return Argon2Params{}, nil, nil, ErrUnknownHash
+	}
+	if params.Memory > maxArgon2Memory || params.Iterations > maxArgon2Iterations {
+		return Argon2Params{}, nil, nil, ErrUnknownHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return Argon2Params{}, nil, nil, ErrUnknownHash
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return Argon2Params{}, nil, nil, ErrUnknownHash
+	}
+
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(key))
+	return params, salt, key, nil
+}
@@ -0,0 +1,129 @@
+package user
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// bcryptTestCost : Keeps the tests fast
+const bcryptTestCost = bcrypt.MinCost
+
+var argon2TestParams = Argon2Params{
+	Memory:      1024,
+	Iterations:  1,
+	Parallelism: 1,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+func TestPasswordHasher(t *testing.T) {
+	hashers := map[string]*PasswordHasher{
+		"argon2id": NewArgon2idHasher(argon2TestParams),
+		"bcrypt":   NewBcryptHasher(bcryptTestCost),
+	}
+
+	for name, hasher := range hashers {
+		t.Run(name, func(t *testing.T) {
+			hash, err := hasher.Hash("secret")
+			require.NoError(t, err)
+			assert.NotContains(t, hash, "secret")
+
+			other, err := hasher.Hash("secret")
+			require.NoError(t, err)
+			assert.NotEqual(t, hash, other, "hashes should be salted")
+
+			match, rehash, err := hasher.Verify("secret", hash)
+			assert.NoError(t, err)
+			assert.True(t, match)
+			assert.False(t, rehash)
+
+			match, rehash, err = hasher.Verify("wrong", hash)
+			assert.NoError(t, err)
+			assert.False(t, match)
+			assert.False(t, rehash)
+		})
+	}
+}
+
+func TestPasswordHasherArgon2idFormat(t *testing.T) {
+	hash, err := NewArgon2idHasher(argon2TestParams).Hash("secret")
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=1024,t=1,p=1$"), hash)
+}
+
+func TestPasswordHasherRehash(t *testing.T) {
+	argon2Hasher := NewArgon2idHasher(argon2TestParams)
+	bcryptHasher := NewBcryptHasher(bcryptTestCost)
+
+	argon2Hash, err := argon2Hasher.Hash("secret")
+	require.NoError(t, err)
+	bcryptHash, err := bcryptHasher.Hash("secret")
+	require.NoError(t, err)
+
+	stronger := argon2TestParams
+	stronger.Iterations = 2
+
+	tests := []struct {
+		name   string
+		hasher *PasswordHasher
+		hash   string
+		rehash bool
+	}{
+		{"Same argon2id parameters", argon2Hasher, argon2Hash, false},
+		{"Other argon2id parameters", NewArgon2idHasher(stronger), argon2Hash, true},
+		{"bcrypt to argon2id", argon2Hasher, bcryptHash, true},
+		{"argon2id to bcrypt", bcryptHasher, argon2Hash, true},
+		{"Other bcrypt cost", NewBcryptHasher(bcryptTestCost + 1), bcryptHash, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, rehash, err := tt.hasher.Verify("secret", tt.hash)
+			assert.NoError(t, err)
+			assert.True(t, match)
+			assert.Equal(t, tt.rehash, rehash)
+		})
+	}
+}
+
+func TestPasswordHasherInvalidHash(t *testing.T) {
+	hasher := NewArgon2idHasher(argon2TestParams)
+
+	for _, hash := range []string{
+		"",
+		"$argon2id$v=19$m=1024,t=1,p=1$salt",
+		"$argon2id$v=19$m=1024,t=0,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=4294967295,t=1,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=1024,t=100,p=1$c2FsdA$a2V5",
+		"$argon2i$v=19$m=1024,t=1,p=1$c2FsdA$a2V5",
+	} {
+		match, _, err := hasher.Verify("secret", hash)
+		assert.Error(t, err, hash)
+		assert.False(t, match)
+	}
+}
+
+func TestPasswordHasherLegacyPlaintext(t *testing.T) {
+	hasher := NewArgon2idHasher(argon2TestParams)
+
+	match, _, err := hasher.Verify("secret", "secret")
+	assert.ErrorIs(t, err, ErrUnknownHash, "plaintext passwords should be rejected by default")
+	assert.False(t, match)
+
+	hasher.AllowLegacyPlaintext = true
+
+	match, rehash, err := hasher.Verify("secret", "secret")
+	assert.NoError(t, err)
+	assert.True(t, match)
+	assert.True(t, rehash, "plaintext passwords should be hashed once they match")
+
+	match, rehash, err = hasher.Verify("wrong", "secret")
+	assert.NoError(t, err)
+	assert.False(t, match)
+	assert.False(t, rehash)
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"log"
+	"sync"
 	"time"
 )
 
@@ -15,15 +17,28 @@ type User struct {
 
 type SqlUserRepository struct {
 	db *sql.DB
+	// Hasher of the new passwords, argon2id with DefaultArgon2Params by default
+	Hasher *PasswordHasher
+
+	dummyOnce sync.Once
+	dummy     string
 }
 
 func NewSqlUserRepository(db *sql.DB) *SqlUserRepository {
 	return &SqlUserRepository{
-		db: db,
+		db:     db,
+		Hasher: NewArgon2idHasher(DefaultArgon2Params),
 	}
 }
 
-func (s *SqlUserRepository) CreateUser(ctx context.Context, user *User) (*User, error) {
+// CreateUser : Only the hash of password is stored, in user.PasswordHash
+func (s *SqlUserRepository) CreateUser(ctx context.Context, user *User, password string) (*User, error) {
+	passwordHash, err := s.Hasher.Hash(password)
+	if err != nil {
+		return nil, err
+	}
+	user.PasswordHash = passwordHash
+
 	result, err := s.db.ExecContext(ctx,
 		`INSERT INTO userAuthentication (email, password_hash, last_login) VALUES ($1, $2, $3)`,
 		user.Email,
@@ -61,20 +76,61 @@ func (s *SqlUserRepository) GetUserByEmail(ctx context.Context, email string) (*
 	return user, nil
 }
 
+// IsPasswordMatch : A hash made with another algorithm or other parameters is replaced when the password matches
+// A failed replacement is only logged, the login still succeeds and the hash is replaced on the next one
+// Unknown emails take as long as wrong passwords, so they can not be told apart
 func (s *SqlUserRepository) IsPasswordMatch(ctx context.Context, email, password string) (bool, error) {
 	row := s.db.QueryRowContext(ctx,
-		`SELECT email FROM userAuthentication WHERE email = $1 AND password_hash = $2`,
+		`SELECT password_hash FROM userAuthentication WHERE email = $1`,
 		email,
-		password,
 	)
 
-	var found bool
-	err := row.Scan(&found)
+	var passwordHash string
+	err := row.Scan(&passwordHash)
+	if errors.Is(err, sql.ErrNoRows) {
+		_, _, _ = s.Hasher.Verify(password, s.dummyHash())
+		return false, nil
+	}
 	if err != nil {
 		return false, err
 	}
 
-	return found, nil
+	match, rehash, err := s.Hasher.Verify(password, passwordHash)
+	if err != nil || !match {
+		return false, err
+	}
+
+	if rehash {
+		if err := s.rehash(ctx, email, password, passwordHash); err != nil {
+			log.Printf("failed to rehash the password of %s: %v", email, err)
+		}
+	}
+
+	return true, nil
+}
+
+// rehash : The hash is only replaced when it was not changed since it was verified
+func (s *SqlUserRepository) rehash(ctx context.Context, email, password, previousHash string) error {
+	passwordHash, err := s.Hasher.Hash(password)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.ExecContext(ctx,
+		`UPDATE userAuthentication SET password_hash = $1 WHERE email = $2 AND password_hash = $3`,
+		passwordHash,
+		email,
+		previousHash,
+	)
+	return err
+}
+
+// dummyHash : Hash verified for the unknown emails, made once per hasher
+func (s *SqlUserRepository) dummyHash() string {
+	s.dummyOnce.Do(func() {
+		s.dummy, _ = s.Hasher.Hash("")
+	})
+	return s.dummy
 }
 
 func (s *SqlUserRepository) DeleteUserByEmail(ctx context.Context, email string) error {
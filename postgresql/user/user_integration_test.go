@@ -8,6 +8,7 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strings"
 	"syscall"
 	"testing"
 	"time"
@@ -139,12 +140,11 @@ func TestPostgreslSessionRepository_Scenario(t *testing.T) {
 		userRepository := NewSqlUserRepository(databaseConnection)
 
 		user := &User{
-			Email:        "test@test.com",
-			PasswordHash: "password",
-			LastLogin:    time.Now().UTC(),
+			Email:     "test@test.com",
+			LastLogin: time.Now().UTC(),
 		}
 
-		_, err := userRepository.CreateUser(ctx, user)
+		_, err := userRepository.CreateUser(ctx, user, "password")
 		if err != nil {
 			t.Fatalf("Failed to create user: %v", err)
 		}
@@ -156,6 +156,7 @@ func TestPostgreslSessionRepository_Scenario(t *testing.T) {
 
 		assert.Equal(t, user.Email, savedUser.Email)
 		assert.Equal(t, user.PasswordHash, savedUser.PasswordHash)
+		assert.NotContains(t, savedUser.PasswordHash, "password")
 		assert.Equal(t, user.LastLogin.Unix(), savedUser.LastLogin.Unix())
 
 		err = userRepository.DeleteUserByEmail(ctx, user.Email)
@@ -167,6 +168,86 @@ func TestPostgreslSessionRepository_Scenario(t *testing.T) {
 		assert.NotNil(t, err, "error should not be nil")
 	})
 
+	t.Run("Password match", func(t *testing.T) {
+		userRepository := NewSqlUserRepository(databaseConnection)
+
+		user := &User{
+			Email:     "match@test.com",
+			LastLogin: time.Now().UTC(),
+		}
+		_, err := userRepository.CreateUser(ctx, user, "secret")
+		assert.NoError(t, err)
+
+		match, err := userRepository.IsPasswordMatch(ctx, user.Email, "secret")
+		assert.NoError(t, err)
+		assert.True(t, match)
+
+		match, err = userRepository.IsPasswordMatch(ctx, user.Email, "wrong")
+		assert.NoError(t, err)
+		assert.False(t, match)
+
+		match, err = userRepository.IsPasswordMatch(ctx, "unknown@test.com", "secret")
+		assert.NoError(t, err)
+		assert.False(t, match)
+	})
+
+	t.Run("Rehash on login", func(t *testing.T) {
+		userRepository := NewSqlUserRepository(databaseConnection)
+		userRepository.Hasher = NewBcryptHasher(bcryptTestCost)
+
+		user := &User{
+			Email:     "rehash@test.com",
+			LastLogin: time.Now().UTC(),
+		}
+		_, err := userRepository.CreateUser(ctx, user, "secret")
+		assert.NoError(t, err)
+
+		userRepository.Hasher = NewArgon2idHasher(DefaultArgon2Params)
+
+		// A wrong password does not rehash
+		match, err := userRepository.IsPasswordMatch(ctx, user.Email, "wrong")
+		assert.NoError(t, err)
+		assert.False(t, match)
+
+		savedUser, err := userRepository.GetUserByEmail(ctx, user.Email)
+		assert.NoError(t, err)
+		assert.Equal(t, user.PasswordHash, savedUser.PasswordHash)
+
+		match, err = userRepository.IsPasswordMatch(ctx, user.Email, "secret")
+		assert.NoError(t, err)
+		assert.True(t, match)
+
+		savedUser, err = userRepository.GetUserByEmail(ctx, user.Email)
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(savedUser.PasswordHash, "$argon2id$"))
+
+		match, err = userRepository.IsPasswordMatch(ctx, user.Email, "secret")
+		assert.NoError(t, err)
+		assert.True(t, match)
+	})
+
+	t.Run("Plaintext password is hashed on login", func(t *testing.T) {
+		userRepository := NewSqlUserRepository(databaseConnection)
+		userRepository.Hasher.AllowLegacyPlaintext = true
+
+		_, err := databaseConnection.ExecContext(ctx,
+			`INSERT INTO userAuthentication (email, password_hash, last_login) VALUES ($1, $2, $3)`,
+			"legacy@test.com", "secret", time.Now().UTC())
+		assert.NoError(t, err)
+
+		match, err := userRepository.IsPasswordMatch(ctx, "legacy@test.com", "wrong")
+		assert.NoError(t, err)
+		assert.False(t, match)
+
+		match, err = userRepository.IsPasswordMatch(ctx, "legacy@test.com", "secret")
+		assert.NoError(t, err)
+		assert.True(t, match)
+
+		savedUser, err := userRepository.GetUserByEmail(ctx, "legacy@test.com")
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(savedUser.PasswordHash, "$argon2id$"))
+	})
+
 }
 
 func freezeContainerHelper() {
@@ -0,0 +1,93 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+type BreakerState int
+
+const (
+	// BreakerClosed : Calls go to Redis
+	BreakerClosed BreakerState = iota
+	// BreakerOpen : Redis is considered down, calls are not sent until the open timeout
+	BreakerOpen
+	// BreakerHalfOpen : One trial call decides whether the breaker closes or opens again
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreaker : Opens after threshold consecutive failures, so a dead Redis is not called for every request
+type CircuitBreaker struct {
+	mu          sync.Mutex
+	threshold   int
+	openTimeout time.Duration
+	state       BreakerState
+	failures    int
+	// changedAt is when the breaker opened, or when the trial call started
+	changedAt time.Time
+}
+
+func NewCircuitBreaker(threshold int, openTimeout time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		threshold:   max(threshold, 1),
+		openTimeout: openTimeout,
+	}
+}
+
+// Ready : Whether a call may be sent, the first call after the open timeout is the trial call
+// A trial call that never reports is replaced after another open timeout
+func (b *CircuitBreaker) Ready() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerClosed {
+		return true
+	}
+
+	if time.Since(b.changedAt) < b.openTimeout {
+		return false
+	}
+
+	b.state = BreakerHalfOpen
+	b.changedAt = time.Now()
+	return true
+}
+
+func (b *CircuitBreaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = BreakerClosed
+	b.failures = 0
+}
+
+func (b *CircuitBreaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	if b.state == BreakerHalfOpen || b.failures >= b.threshold {
+		b.state = BreakerOpen
+		b.changedAt = time.Now()
+	}
+}
+
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
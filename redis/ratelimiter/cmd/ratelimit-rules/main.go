@@ -0,0 +1,113 @@
+// ratelimit-rules lists, sets and deletes the rate limit rules kept in Redis
+//
+//	ratelimit-rules [-addr localhost:6379] [-hash tinyurl:ratelimit:rules] list
+//	ratelimit-rules set <match> allow|deny
+//	ratelimit-rules set <match> limit <limit> <window>
+//	ratelimit-rules delete <match>
+//
+// Matches are IPs, CIDRs such as 10.0.0.0/8, or API keys for anything else
+// API keys are stored as their SHA-256, list prints the hash, delete takes the key or its hash
+// The default hash is the RATE_LIMIT_RULES_HASH default of tinyurl
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	ratelimit "github.com/christapa/testContainers/redis/ratelimiter"
+	"github.com/redis/go-redis/v9"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:6379", "Redis address")
+	password := flag.String("password", "", "Redis password")
+	hash := flag.String("hash", "tinyurl:ratelimit:rules", "Redis hash holding the rules, RATE_LIMIT_RULES_HASH of tinyurl")
+	flag.Parse()
+
+	client := redis.NewClient(&redis.Options{
+		Addr:     *addr,
+		Password: *password,
+	})
+	defer client.Close()
+
+	store := ratelimit.NewRuleStore(client, *hash, 0)
+	if err := run(context.Background(), store, flag.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, store *ratelimit.RuleStore, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("expected list, set or delete")
+	}
+
+	switch args[0] {
+	case "list":
+		rules, err := store.List(ctx)
+		if err != nil {
+			return err
+		}
+
+		writer := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(writer, "MATCH\tACTION\tLIMIT\tWINDOW")
+		for _, rule := range rules {
+			if rule.Action == ratelimit.RuleLimit {
+				fmt.Fprintf(writer, "%s\t%s\t%d\t%s\n", rule.Match, rule.Action, rule.Limit, rule.Window)
+			} else {
+				fmt.Fprintf(writer, "%s\t%s\t-\t-\n", rule.Match, rule.Action)
+			}
+		}
+		return writer.Flush()
+
+	case "set":
+		rule, err := parseRule(args[1:])
+		if err != nil {
+			return err
+		}
+		return store.Set(ctx, rule)
+
+	case "delete":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: delete <match>")
+		}
+		return store.Delete(ctx, args[1])
+
+	default:
+		return fmt.Errorf("unknown command %q, expected list, set or delete", args[0])
+	}
+}
+
+func parseRule(args []string) (ratelimit.Rule, error) {
+	if len(args) < 2 {
+		return ratelimit.Rule{}, fmt.Errorf("usage: set <match> allow|deny|limit [limit window]")
+	}
+
+	rule := ratelimit.Rule{Match: args[0], Action: ratelimit.RuleAction(args[1])}
+	if rule.Action != ratelimit.RuleLimit {
+		return rule, rule.Validate()
+	}
+
+	if len(args) != 4 {
+		return ratelimit.Rule{}, fmt.Errorf("usage: set <match> limit <limit> <window>")
+	}
+
+	limit, err := strconv.ParseInt(args[2], 10, 64)
+	if err != nil {
+		return ratelimit.Rule{}, fmt.Errorf("invalid limit %q: %w", args[2], err)
+	}
+
+	window, err := time.ParseDuration(args[3])
+	if err != nil {
+		return ratelimit.Rule{}, fmt.Errorf("invalid window %q: %w", args[3], err)
+	}
+
+	rule.Limit = limit
+	rule.Window = window
+	return rule, rule.Validate()
+}
@@ -0,0 +1,110 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// FailurePolicy : Decision taken when Redis can not be reached
+type FailurePolicy int
+
+const (
+	// FailOpen : Every request is allowed
+	FailOpen FailurePolicy = iota
+	// FailClosed : Every request is rejected
+	FailClosed
+	// FailLocal : Requests are limited in memory by a LocalLimiter
+	FailLocal
+)
+
+func ParseFailurePolicy(policy string) (FailurePolicy, error) {
+	switch policy {
+	case "open":
+		return FailOpen, nil
+	case "closed":
+		return FailClosed, nil
+	case "local":
+		return FailLocal, nil
+	default:
+		return FailOpen, fmt.Errorf("unknown failure policy %q, expected open, closed or local", policy)
+	}
+}
+
+// FailSafeLimiter : Apply the failure policy instead of returning the errors of the limiter
+// The circuit breaker skips the limiter while Redis is down, the results given then are Degraded
+type FailSafeLimiter struct {
+	limiter Limiter
+	policy  FailurePolicy
+	breaker *CircuitBreaker
+	local   *LocalLimiter
+}
+
+// NewFailSafeLimiter : local is only used by FailLocal, which fails open without it
+func NewFailSafeLimiter(limiter Limiter, policy FailurePolicy, breaker *CircuitBreaker, local *LocalLimiter) *FailSafeLimiter {
+	if policy == FailLocal && local == nil {
+		policy = FailOpen
+	}
+
+	return &FailSafeLimiter{
+		limiter: limiter,
+		policy:  policy,
+		breaker: breaker,
+		local:   local,
+	}
+}
+
+// Allow : Only the errors of a cancelled context are returned, they say nothing about Redis
+// The key is taken as the IP of the subject
+func (f *FailSafeLimiter) Allow(ctx context.Context, key string) (Result, error) {
+	return f.AllowSubject(ctx, Subject{IP: key}, key)
+}
+
+// AllowSubject : The subject is given to the limiter when it is a SubjectLimiter
+// The failure policy ignores the subject, the rules of an OverrideLimiter do not apply while Redis is down
+func (f *FailSafeLimiter) AllowSubject(ctx context.Context, subject Subject, key string) (Result, error) {
+	if f.breaker.Ready() {
+		result, err := f.allow(ctx, subject, key)
+		if err == nil {
+			f.breaker.Success()
+			return result, nil
+		}
+
+		if ctx.Err() != nil {
+			return Result{}, err
+		}
+
+		f.breaker.Failure()
+	}
+
+	return f.fallback(ctx, key)
+}
+
+func (f *FailSafeLimiter) allow(ctx context.Context, subject Subject, key string) (Result, error) {
+	if limiter, ok := f.limiter.(SubjectLimiter); ok {
+		return limiter.AllowSubject(ctx, subject, key)
+	}
+
+	return f.limiter.Allow(ctx, key)
+}
+
+func (f *FailSafeLimiter) fallback(ctx context.Context, key string) (Result, error) {
+	switch f.policy {
+	case FailClosed:
+		return Result{
+			ResetAt:    time.Now().Add(f.breaker.openTimeout),
+			RetryAfter: f.breaker.openTimeout,
+			Degraded:   true,
+		}, nil
+	case FailLocal:
+		result, err := f.local.Allow(ctx, key)
+		result.Degraded = true
+		return result, err
+	default:
+		return Result{Allowed: true, Degraded: true}, nil
+	}
+}
+
+func (f *FailSafeLimiter) BreakerState() BreakerState {
+	return f.breaker.State()
+}
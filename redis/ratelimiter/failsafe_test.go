@@ -0,0 +1,124 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// failingLimiter : Limiter failing while down is set
+type failingLimiter struct {
+	down  bool
+	calls int
+}
+
+func (f *failingLimiter) Allow(_ context.Context, _ string) (Result, error) {
+	f.calls++
+	if f.down {
+		return Result{}, errors.New("connection refused")
+	}
+
+	return Result{Allowed: true, Limit: 10, Remaining: 9}, nil
+}
+
+func TestFailSafeLimiterPolicies(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		policy  FailurePolicy
+		allowed int
+	}{
+		{name: "Fail open", policy: FailOpen, allowed: 5},
+		{name: "Fail closed", policy: FailClosed, allowed: 0},
+		{name: "Fail local", policy: FailLocal, allowed: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := NewFailSafeLimiter(&failingLimiter{down: true}, tt.policy, NewCircuitBreaker(3, time.Minute), NewLocalLimiter(time.Minute, 6, 3))
+
+			allowed := 0
+			for range 5 {
+				result, err := limiter.Allow(ctx, "key")
+				assert.NoError(t, err)
+				assert.True(t, result.Degraded)
+				if result.Allowed {
+					allowed++
+				}
+			}
+
+			assert.Equal(t, tt.allowed, allowed)
+		})
+	}
+}
+
+func TestFailSafeLimiterBreaker(t *testing.T) {
+	ctx := context.Background()
+
+	redis := &failingLimiter{down: true}
+	limiter := NewFailSafeLimiter(redis, FailOpen, NewCircuitBreaker(3, 50*time.Millisecond), nil)
+
+	t.Run("Opens after the threshold", func(t *testing.T) {
+		for range 10 {
+			_, err := limiter.Allow(ctx, "key")
+			assert.NoError(t, err)
+		}
+
+		assert.Equal(t, 3, redis.calls, "Redis is not called once the breaker is open")
+		assert.Equal(t, BreakerOpen, limiter.BreakerState())
+	})
+
+	t.Run("Failed trial opens again", func(t *testing.T) {
+		time.Sleep(60 * time.Millisecond)
+
+		_, err := limiter.Allow(ctx, "key")
+		assert.NoError(t, err)
+
+		assert.Equal(t, 4, redis.calls)
+		assert.Equal(t, BreakerOpen, limiter.BreakerState())
+	})
+
+	t.Run("Successful trial closes", func(t *testing.T) {
+		redis.down = false
+		time.Sleep(60 * time.Millisecond)
+
+		result, err := limiter.Allow(ctx, "key")
+		assert.NoError(t, err)
+		assert.False(t, result.Degraded)
+		assert.Equal(t, BreakerClosed, limiter.BreakerState())
+	})
+}
+
+func TestFailSafeLimiterCancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	breaker := NewCircuitBreaker(1, time.Minute)
+	limiter := NewFailSafeLimiter(&failingLimiter{down: true}, FailOpen, breaker, nil)
+
+	_, err := limiter.Allow(ctx, "key")
+	assert.Error(t, err)
+	assert.Equal(t, BreakerClosed, breaker.State(), "A cancelled request says nothing about Redis")
+}
+
+func TestLocalLimiterShare(t *testing.T) {
+	ctx := context.Background()
+
+	limiter := NewLocalLimiter(time.Minute, 10, 4)
+
+	allowed := 0
+	for range 5 {
+		result, err := limiter.Allow(ctx, "key")
+		assert.NoError(t, err)
+		if result.Allowed {
+			allowed++
+		}
+	}
+
+	// 10 requests shared by 4 instances
+	assert.Equal(t, 2, allowed)
+}
@@ -0,0 +1,111 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// GCRA : Generic Cell Rate Algorithm, one request every rate period, bursts of up to burst requests
+// Only the theoretical arrival time of the next request is stored, in Redis time so client clocks do not matter
+type GCRA struct {
+	client *redis.Client
+	// interval is the time between two requests at the sustained rate
+	interval time.Duration
+	burst    int64
+}
+
+// NewGCRA : rate requests every period, burst and rate must be positive
+// The script counts in microseconds, the interval between two requests can not be below 1µs
+func NewGCRA(client *redis.Client, rate int64, per time.Duration, burst int64) (*GCRA, error) {
+	if rate <= 0 || burst <= 0 {
+		return nil, fmt.Errorf("rate %d and burst %d must be positive", rate, burst)
+	}
+
+	interval := per / time.Duration(rate)
+	if interval < time.Microsecond {
+		return nil, fmt.Errorf("interval %s of %d requests every %s is below 1µs", interval, rate, per)
+	}
+
+	return &GCRA{
+		client:   client,
+		interval: interval,
+		burst:    burst,
+	}, nil
+}
+
+// gcraScript : tat is the theoretical arrival time in microseconds
+// A request of cost n is allowed when tat + n * interval is at most burst * interval ahead of now
+var gcraScript = redis.NewScript(`
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000000 + tonumber(time[2])
+local interval = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local cost = tonumber(ARGV[3])
+local tolerance = interval * burst
+
+local tat = math.max(tonumber(redis.call("GET", KEYS[1])) or now, now)
+local new_tat = tat + cost * interval
+local allow_at = new_tat - tolerance
+
+if allow_at > now then
+	return {0, math.floor((now + tolerance - tat) / interval), math.ceil((allow_at - now) / 1000), math.ceil((tat - now) / 1000)}
+end
+
+local reset = math.ceil((new_tat - now) / 1000)
+redis.call("SET", KEYS[1], new_tat, "PX", reset)
+return {1, math.floor((now + tolerance - new_tat) / interval), 0, reset}
+`)
+
+func (g *GCRA) Allow(ctx context.Context, key string) (Result, error) {
+	return g.AllowN(ctx, key, 1)
+}
+
+// AllowN : Count a request costing n, nothing is consumed when it is rejected
+// A cost above the burst can never be allowed
+func (g *GCRA) AllowN(ctx context.Context, key string, n int64) (Result, error) {
+	if n <= 0 || n > g.burst {
+		return Result{}, fmt.Errorf("cost %d must be between 1 and the burst %d", n, g.burst)
+	}
+
+	values, err := gcraScript.Run(ctx, g.client, []string{key},
+		g.interval.Microseconds(),
+		g.burst,
+		n,
+	).Int64Slice()
+	if err != nil {
+		return Result{}, err
+	}
+
+	return newScriptResult(values, g.burst), nil
+}
+
+// Wait : Block until a request is allowed or the context is done
+func (g *GCRA) Wait(ctx context.Context, key string) error {
+	return g.WaitN(ctx, key, 1)
+}
+
+// WaitN : Block until a request costing n is allowed or the context is done
+// Other clients may take the slot first, the request is then retried after the new delay
+func (g *GCRA) WaitN(ctx context.Context, key string, n int64) error {
+	for {
+		result, err := g.AllowN(ctx, key, n)
+		if err != nil {
+			return err
+		}
+
+		if result.Allowed {
+			return nil
+		}
+
+		timer := time.NewTimer(result.RetryAfter)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Limiter : Rate limit algorithm, every call counts one request of the key
+type Limiter interface {
+	Allow(ctx context.Context, key string) (Result, error)
+}
+
+var (
+	_ Limiter = (*RateLimiter)(nil)
+	_ Limiter = (*SlidingWindowLog)(nil)
+	_ Limiter = (*SlidingWindowCounter)(nil)
+	_ Limiter = (*TokenBucket)(nil)
+	_ Limiter = (*GCRA)(nil)
+	_ Limiter = (*Policy)(nil)
+	_ Limiter = (*LocalLimiter)(nil)
+
+	_ SubjectLimiter = (*OverrideLimiter)(nil)
+	_ SubjectLimiter = (*FailSafeLimiter)(nil)
+)
+
+// newScriptResult : The scripts return {allowed, remaining, retry after in ms, reset in ms}
+// Durations are relative to the Redis clock, they are turned into local times here
+func newScriptResult(values []int64, limit int64) Result {
+	result := Result{
+		Allowed:   values[0] == 1,
+		Limit:     limit,
+		Remaining: values[1],
+		ResetAt:   time.Now().Add(time.Duration(values[3]) * time.Millisecond),
+	}
+
+	if !result.Allowed {
+		result.RetryAfter = time.Duration(values[2]) * time.Millisecond
+	}
+
+	return result
+}
@@ -0,0 +1,72 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// localPurgeEvery : Expired windows are removed every localPurgeEvery calls
+const localPurgeEvery = 1024
+
+type localWindow struct {
+	count   int64
+	resetAt time.Time
+}
+
+// LocalLimiter : Fixed window kept in memory, for when Redis can not be reached
+// Every instance counts on its own, so each one allows its share of the global limit
+type LocalLimiter struct {
+	mu      sync.Mutex
+	window  time.Duration
+	limit   int64
+	windows map[string]localWindow
+	calls   int
+}
+
+// NewLocalLimiter : limit is the global limit shared by instances processes
+func NewLocalLimiter(window time.Duration, limit int64, instances int64) *LocalLimiter {
+	return &LocalLimiter{
+		window:  window,
+		limit:   max(limit/max(instances, 1), 1),
+		windows: make(map[string]localWindow),
+	}
+}
+
+func (l *LocalLimiter) Allow(_ context.Context, key string) (Result, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+
+	l.calls++
+	if l.calls%localPurgeEvery == 0 {
+		for windowKey, window := range l.windows {
+			if !now.Before(window.resetAt) {
+				delete(l.windows, windowKey)
+			}
+		}
+	}
+
+	window, found := l.windows[key]
+	if !found || !now.Before(window.resetAt) {
+		window = localWindow{resetAt: now.Add(l.window)}
+	}
+
+	result := Result{
+		Limit:   l.limit,
+		ResetAt: window.resetAt,
+	}
+
+	if window.count >= l.limit {
+		result.RetryAfter = window.resetAt.Sub(now)
+		return result, nil
+	}
+
+	window.count++
+	l.windows[key] = window
+
+	result.Allowed = true
+	result.Remaining = l.limit - window.count
+	return result, nil
+}
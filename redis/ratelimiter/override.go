@@ -0,0 +1,59 @@
+package ratelimit
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// SubjectLimiter : Limiter whose decision also depends on who sends the request, not only on the counter key
+type SubjectLimiter interface {
+	Limiter
+	AllowSubject(ctx context.Context, subject Subject, key string) (Result, error)
+}
+
+// OverrideLimiter : Apply the rule of the subject before the default limiter
+// Subjects with a limit rule are counted by a fixed window on their own key
+type OverrideLimiter struct {
+	client  *redis.Client
+	limiter Limiter
+	rules   *RuleStore
+}
+
+func NewOverrideLimiter(client *redis.Client, limiter Limiter, rules *RuleStore) *OverrideLimiter {
+	return &OverrideLimiter{
+		client:  client,
+		limiter: limiter,
+		rules:   rules,
+	}
+}
+
+// Allow : The key is taken as the IP of the subject, only the IP rules apply
+func (o *OverrideLimiter) Allow(ctx context.Context, key string) (Result, error) {
+	return o.AllowSubject(ctx, Subject{IP: key}, key)
+}
+
+// AllowSubject : Allowed subjects have a result without limit, denied ones a Denied result
+func (o *OverrideLimiter) AllowSubject(ctx context.Context, subject Subject, key string) (Result, error) {
+	rule, found, err := o.rules.Match(ctx, subject)
+	if err != nil {
+		return Result{}, err
+	}
+
+	if !found {
+		return o.limiter.Allow(ctx, key)
+	}
+
+	switch rule.Action {
+	case RuleAllow:
+		return Result{Allowed: true}, nil
+	case RuleDeny:
+		return Result{Denied: true}, nil
+	default:
+		limiter, err := NewRateLimiter(o.client, rule.Window, rule.Limit)
+		if err != nil {
+			return Result{}, err
+		}
+		return limiter.Allow(ctx, key+":override")
+	}
+}
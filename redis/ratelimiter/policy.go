@@ -0,0 +1,137 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Tier : Fixed window of a policy, the name is part of the Redis key and must be unique in the policy
+type Tier struct {
+	Name   string
+	Limit  int64
+	Window time.Duration
+}
+
+func PerSecond(limit int64) Tier {
+	return Tier{Name: "second", Limit: limit, Window: time.Second}
+}
+
+func PerMinute(limit int64) Tier {
+	return Tier{Name: "minute", Limit: limit, Window: time.Minute}
+}
+
+func PerHour(limit int64) Tier {
+	return Tier{Name: "hour", Limit: limit, Window: time.Hour}
+}
+
+func PerDay(limit int64) Tier {
+	return Tier{Name: "day", Limit: limit, Window: 24 * time.Hour}
+}
+
+// Policy : Several fixed windows on the same key, such as 10/s, 300/min and 10k/day
+// A request is counted in every tier, or in none when one of them rejects it
+type Policy struct {
+	client *redis.Client
+	tiers  []Tier
+}
+
+func NewPolicy(client *redis.Client, tiers ...Tier) *Policy {
+	return &Policy{
+		client: client,
+		tiers:  tiers,
+	}
+}
+
+// policyScript : ARGV holds the limit and the window in ms of each key
+// Keys without expiration, left by a failed PEXPIRE, are given one again so their tier keeps limiting
+// Returns {allowed, tier index from 1, remaining, retry after in ms, reset in ms}
+// A rejected request reports the tier to wait for the longest, an allowed one the tier with the least remaining
+var policyScript = redis.NewScript(`
+local counts = {}
+local ttls = {}
+local rejected = 0
+local retry = 0
+
+for i, key in ipairs(KEYS) do
+	local limit = tonumber(ARGV[i * 2 - 1])
+	local window = tonumber(ARGV[i * 2])
+	local ttl = redis.call("PTTL", key)
+	local count = 0
+	if ttl == -1 then
+		-- A key left without expiration, as the fixed window its count is kept for a new window
+		redis.call("PEXPIRE", key, window)
+		ttl = window
+	end
+	if ttl >= 0 then
+		count = tonumber(redis.call("GET", key)) or 0
+	else
+		ttl = window
+	end
+	counts[i] = count
+	ttls[i] = ttl
+
+	if count + 1 > limit and (rejected == 0 or ttl > retry) then
+		rejected = i
+		retry = ttl
+	end
+end
+
+if rejected > 0 then
+	return {0, rejected, 0, retry, ttls[rejected]}
+end
+
+local tier = 0
+local remaining = -1
+for i, key in ipairs(KEYS) do
+	local limit = tonumber(ARGV[i * 2 - 1])
+	local count = redis.call("INCR", key)
+	if count == 1 or redis.call("PTTL", key) < 0 then
+		redis.call("PEXPIRE", key, ARGV[i * 2])
+	end
+
+	if remaining < 0 or limit - count < remaining then
+		tier = i
+		remaining = limit - count
+	end
+end
+
+return {1, tier, remaining, 0, ttls[tier]}
+`)
+
+// Allow : Check every tier in one round trip
+// Result.Tier names the tier that rejected the request, or the closest to its limit when allowed
+func (p *Policy) Allow(ctx context.Context, key string) (Result, error) {
+	if len(p.tiers) == 0 {
+		return Result{Allowed: true}, nil
+	}
+
+	keys := make([]string, 0, len(p.tiers))
+	args := make([]any, 0, len(p.tiers)*2)
+	for _, tier := range p.tiers {
+		// The hash tag keeps the tiers of a key on the same cluster slot
+		keys = append(keys, "{"+key+"}:"+tier.Name)
+		args = append(args, tier.Limit, tier.Window.Milliseconds())
+	}
+
+	values, err := policyScript.Run(ctx, p.client, keys, args...).Int64Slice()
+	if err != nil {
+		return Result{}, err
+	}
+
+	tier := p.tiers[values[1]-1]
+	result := Result{
+		Allowed:   values[0] == 1,
+		Limit:     tier.Limit,
+		Remaining: values[2],
+		ResetAt:   time.Now().Add(time.Duration(values[4]) * time.Millisecond),
+		Tier:      tier.Name,
+	}
+
+	if !result.Allowed {
+		result.RetryAfter = time.Duration(values[3]) * time.Millisecond
+	}
+
+	return result, nil
+}
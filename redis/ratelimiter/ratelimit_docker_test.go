@@ -25,7 +25,8 @@ func TestRateLimiter(t *testing.T) {
 	assert.NoError(t, err)
 
 	rate := int64(3)
-	limiter := NewRateLimiter(client, 5*time.Second, rate)
+	limiter, err := NewRateLimiter(client, 5*time.Second, rate)
+	assert.NoError(t, err)
 
 	t.Run("No limit reached", func(t *testing.T) {
 		ip := "192.168.1.50"
@@ -57,3 +58,56 @@ func TestRateLimiter(t *testing.T) {
 		assert.Equal(t, client.Get(ctx, ip).Val(), "4")
 	})
 }
+
+func TestRateLimiterAllow(t *testing.T) {
+	ctx := context.Background()
+
+	client, err := newRedisClient()
+	assert.NoError(t, err)
+
+	rate := int64(2)
+	limiter, err := NewRateLimiter(client, 5*time.Second, rate)
+	assert.NoError(t, err)
+
+	key := "192.168.1.60"
+	client.Del(ctx, key)
+
+	t.Run("Remaining decreases", func(t *testing.T) {
+		result, err := limiter.Allow(ctx, key)
+		assert.NoError(t, err)
+
+		assert.True(t, result.Allowed)
+		assert.Equal(t, rate, result.Limit)
+		assert.Equal(t, int64(1), result.Remaining)
+		assert.Zero(t, result.RetryAfter)
+		assert.WithinDuration(t, time.Now().Add(5*time.Second), result.ResetAt, time.Second)
+
+		// The key expires with the window
+		assert.Greater(t, client.PTTL(ctx, key).Val(), time.Duration(0))
+	})
+
+	t.Run("Limit reached", func(t *testing.T) {
+		result, err := limiter.Allow(ctx, key)
+		assert.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, int64(0), result.Remaining)
+
+		result, err = limiter.Allow(ctx, key)
+		assert.NoError(t, err)
+		assert.False(t, result.Allowed)
+		assert.Equal(t, int64(0), result.Remaining)
+		assert.Greater(t, result.RetryAfter, time.Duration(0))
+		assert.LessOrEqual(t, result.RetryAfter, 5*time.Second)
+	})
+
+	t.Run("Key without expiration is repaired", func(t *testing.T) {
+		stuckKey := "192.168.1.61"
+		client.Set(ctx, stuckKey, 10, 0)
+
+		result, err := limiter.Allow(ctx, stuckKey)
+		assert.NoError(t, err)
+		assert.False(t, result.Allowed)
+
+		assert.Greater(t, client.PTTL(ctx, stuckKey).Val(), time.Duration(0))
+	})
+}
@@ -8,40 +8,99 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
+// Result : Outcome of a rate limit check, enough to fill the RateLimit and Retry-After headers
+type Result struct {
+	Allowed   bool
+	Limit     int64
+	Remaining int64
+	// ResetAt is when the quota is fully available again
+	ResetAt time.Time
+	// RetryAfter is zero when the request is allowed
+	RetryAfter time.Duration
+	// Tier is the tier of a Policy the limit and remaining belong to, empty for other limiters
+	Tier string
+	// Degraded is set by FailSafeLimiter when the result comes from its failure policy
+	Degraded bool
+	// Denied is set by OverrideLimiter for the subjects of a deny rule, waiting does not help them
+	Denied bool
+}
+
+// RateLimiter : Fixed window algorithm, the first request of a key starts a window of the given duration
 type RateLimiter struct {
 	client   *redis.Client
 	duration time.Duration
 	rate     int64
 }
 
-func NewRateLimiter(client *redis.Client, windowsDuration time.Duration, rate int64) *RateLimiter {
+// NewRateLimiter : rate must be positive, windowsDuration at least 1ms as the window is set in milliseconds
+func NewRateLimiter(client *redis.Client, windowsDuration time.Duration, rate int64) (*RateLimiter, error) {
+	if err := validateWindow(rate, windowsDuration); err != nil {
+		return nil, err
+	}
+
 	return &RateLimiter{
 		client:   client,
 		duration: windowsDuration,
 		rate:     rate,
-	}
+	}, nil
 }
 
-func (r *RateLimiter) RateLimiter(ctx context.Context, ip string) (bool, error) {
+// validateWindow : Shared by the window limiters, a zero window would never expire the keys
+func validateWindow(limit int64, window time.Duration) error {
+	if limit <= 0 {
+		return fmt.Errorf("limit %d must be positive", limit)
+	}
 
-	key := fmt.Sprintf("%s", ip)
+	if window < time.Millisecond {
+		return fmt.Errorf("window %s is below 1ms", window)
+	}
+
+	return nil
+}
 
-	// Increment the counter for this IP in the current minute window
-	count, err := r.client.Incr(ctx, key).Result()
+// fixedWindowScript : INCR and PEXPIRE in one call, a key can not be left without expiration
+// Keys left without expiration by a previous version are given one
+var fixedWindowScript = redis.NewScript(`
+local count = redis.call("INCR", KEYS[1])
+local ttl = redis.call("PTTL", KEYS[1])
+if count == 1 or ttl < 0 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+	ttl = tonumber(ARGV[1])
+end
+return {count, ttl}
+`)
+
+// Allow : Count the request of the key in its current window
+// Rejected requests are counted too
+func (r *RateLimiter) Allow(ctx context.Context, key string) (Result, error) {
+	values, err := fixedWindowScript.Run(ctx, r.client, []string{key}, r.duration.Milliseconds()).Int64Slice()
 	if err != nil {
-		return false, err
+		return Result{}, err
 	}
 
-	// Set the expiration for this key if this is the first request in the current minute
-	if count == 1 {
-		r.client.Expire(ctx, key, r.duration)
+	count, ttl := values[0], time.Duration(values[1])*time.Millisecond
+
+	result := Result{
+		Allowed:   count <= r.rate,
+		Limit:     r.rate,
+		Remaining: max(r.rate-count, 0),
+		ResetAt:   time.Now().Add(ttl),
 	}
 
-	// Check if the request count exceeds the limit
-	if count > int64(r.rate) {
-		return true, nil
+	if !result.Allowed {
+		result.RetryAfter = ttl
 	}
 
-	return false, nil
+	return result, nil
+}
+
+// RateLimiter : True when the ip is over the limit
+// Deprecated: use Allow, which also tells when the window resets
+func (r *RateLimiter) RateLimiter(ctx context.Context, ip string) (bool, error) {
+	result, err := r.Allow(ctx, ip)
+	if err != nil {
+		return false, err
+	}
 
+	return !result.Allowed, nil
 }
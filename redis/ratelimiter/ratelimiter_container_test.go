@@ -105,7 +105,10 @@ func TestRateLimiterTestContainer(t *testing.T) {
 	assert.NoError(t, err)
 
 	rate := int64(3)
-	limiter := NewRateLimiter(client, 5*time.Second, rate)
+	limiter, err := NewRateLimiter(client, 5*time.Second, rate)
+	if err != nil {
+		t.Fatalf("Failed to create the rate limiter: %v", err)
+	}
 
 	t.Run("No limit reached", func(t *testing.T) {
 		ip := "192.168.1.50"
@@ -137,3 +140,507 @@ func TestRateLimiterTestContainer(t *testing.T) {
 		assert.Equal(t, client.Get(ctx, ip).Val(), "4")
 	})
 }
+
+func newContainerClient(t *testing.T) *redis.Client {
+	ctx := context.Background()
+
+	container, err := initDefaultRedisContainer(ctx)
+	if err != nil {
+		t.Fatalf("Failed to start redis container: %v", err)
+	}
+	t.Cleanup(func() { _ = container.Terminate(ctx) })
+
+	client, err := newRedisClientContainer(ctx, container)
+	if err != nil {
+		t.Fatalf("Failed to create redis client: %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+
+	return client
+}
+
+func countAllowed(t *testing.T, limiter Limiter, key string, requests int) int {
+	allowed := 0
+	for range requests {
+		result, err := limiter.Allow(context.Background(), key)
+		assert.NoError(t, err)
+		if result.Allowed {
+			allowed++
+		}
+	}
+
+	return allowed
+}
+
+// sleepUntilWindow : Sleep until offset after the start of the next window aligned on the clock
+func sleepUntilWindow(window time.Duration, offset time.Duration) {
+	now := time.Now()
+	time.Sleep(now.Truncate(window).Add(window).Add(offset).Sub(now))
+}
+
+// TestBoundaryBurstTestContainer : 1 request, 4 at the end of the window, 5 just after it
+func TestBoundaryBurstTestContainer(t *testing.T) {
+	client := newContainerClient(t)
+
+	burst := func(limiter Limiter, key string) int {
+		allowed := countAllowed(t, limiter, key, 1)
+		time.Sleep(900 * time.Millisecond)
+		allowed += countAllowed(t, limiter, key, 4)
+		time.Sleep(150 * time.Millisecond)
+		return allowed + countAllowed(t, limiter, key, 5)
+	}
+
+	t.Run("Fixed window lets twice the limit through", func(t *testing.T) {
+		limiter, err := NewRateLimiter(client, time.Second, 5)
+		if err != nil {
+			t.Fatalf("Failed to create the rate limiter: %v", err)
+		}
+
+		allowed := burst(limiter, "burst:fixed")
+		assert.Equal(t, 10, allowed)
+	})
+
+	t.Run("Sliding window log keeps the limit", func(t *testing.T) {
+		limiter, err := NewSlidingWindowLog(client, time.Second, 5)
+		if err != nil {
+			t.Fatalf("Failed to create the sliding window log: %v", err)
+		}
+
+		allowed := burst(limiter, "burst:log")
+
+		// Only the first request left the window
+		assert.Equal(t, 6, allowed)
+	})
+}
+
+func TestSlidingWindowLogTestContainer(t *testing.T) {
+	ctx := context.Background()
+	client := newContainerClient(t)
+
+	limiter, err := NewSlidingWindowLog(client, time.Second, 3)
+	if err != nil {
+		t.Fatalf("Failed to create the sliding window log: %v", err)
+	}
+
+	assert.Equal(t, 3, countAllowed(t, limiter, "log", 3))
+
+	result, err := limiter.Allow(ctx, "log")
+	assert.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, int64(3), result.Limit)
+	assert.Greater(t, result.RetryAfter, time.Duration(0))
+	assert.LessOrEqual(t, result.RetryAfter, time.Second)
+
+	// Rejected requests are not logged
+	assert.Equal(t, int64(3), client.ZCard(ctx, "log").Val())
+
+	// The three requests leave the window together
+	time.Sleep(result.RetryAfter + 10*time.Millisecond)
+	assert.Equal(t, 3, countAllowed(t, limiter, "log", 4))
+}
+
+func TestSlidingWindowCounterTestContainer(t *testing.T) {
+	ctx := context.Background()
+	client := newContainerClient(t)
+
+	window := time.Second
+	limiter, err := NewSlidingWindowCounter(client, window, 5)
+	if err != nil {
+		t.Fatalf("Failed to create the sliding window counter: %v", err)
+	}
+
+	t.Run("Limit reached", func(t *testing.T) {
+		sleepUntilWindow(window, 100*time.Millisecond)
+		assert.Equal(t, 5, countAllowed(t, limiter, "counter", 6))
+
+		result, err := limiter.Allow(ctx, "counter")
+		assert.NoError(t, err)
+		assert.False(t, result.Allowed)
+		assert.Equal(t, int64(0), result.Remaining)
+		assert.Greater(t, result.RetryAfter, time.Duration(0))
+	})
+
+	t.Run("Previous window weighs after the boundary", func(t *testing.T) {
+		sleepUntilWindow(window, 50*time.Millisecond)
+
+		// The previous window still counts for about 95%
+		assert.LessOrEqual(t, countAllowed(t, limiter, "counter", 5), 1)
+	})
+
+	t.Run("Previous window fades out", func(t *testing.T) {
+		sleepUntilWindow(window, 0)
+		time.Sleep(window)
+
+		assert.Equal(t, 5, countAllowed(t, limiter, "counter", 5))
+	})
+}
+
+func TestTokenBucketTestContainer(t *testing.T) {
+	ctx := context.Background()
+	client := newContainerClient(t)
+
+	limiter, err := NewTokenBucket(client, 5, 10, time.Second)
+	if err != nil {
+		t.Fatalf("Failed to create the token bucket: %v", err)
+	}
+
+	t.Run("Burst up to the capacity", func(t *testing.T) {
+		assert.Equal(t, 5, countAllowed(t, limiter, "bucket", 5))
+
+		result, err := limiter.Allow(ctx, "bucket")
+		assert.NoError(t, err)
+		assert.False(t, result.Allowed)
+		assert.Equal(t, int64(5), result.Limit)
+		assert.Greater(t, result.RetryAfter, time.Duration(0))
+		assert.LessOrEqual(t, result.RetryAfter, 100*time.Millisecond)
+	})
+
+	t.Run("Refill rate", func(t *testing.T) {
+		time.Sleep(250 * time.Millisecond)
+
+		assert.Equal(t, 2, countAllowed(t, limiter, "bucket", 3))
+	})
+
+	t.Run("Key expires once the bucket is full", func(t *testing.T) {
+		time.Sleep(600 * time.Millisecond)
+
+		assert.Equal(t, int64(0), client.Exists(ctx, "bucket").Val())
+	})
+}
+
+func TestNewWindowLimitersInvalid(t *testing.T) {
+	for _, tt := range []struct {
+		limit  int64
+		window time.Duration
+	}{
+		{limit: 0, window: time.Second},
+		{limit: -1, window: time.Second},
+		{limit: 5, window: 0},
+		{limit: 5, window: time.Millisecond / 2},
+	} {
+		_, err := NewRateLimiter(nil, tt.window, tt.limit)
+		assert.Error(t, err, "%+v", tt)
+
+		_, err = NewSlidingWindowLog(nil, tt.window, tt.limit)
+		assert.Error(t, err, "%+v", tt)
+
+		_, err = NewSlidingWindowCounter(nil, tt.window, tt.limit)
+		assert.Error(t, err, "%+v", tt)
+	}
+}
+
+func TestNewTokenBucketInvalid(t *testing.T) {
+	for _, tt := range []struct {
+		capacity int64
+		rate     int64
+		per      time.Duration
+	}{
+		{capacity: 0, rate: 10, per: time.Second},
+		{capacity: 5, rate: 0, per: time.Second},
+		{capacity: 5, rate: 10, per: 0},
+		{capacity: 5, rate: 1000, per: time.Millisecond / 2},
+	} {
+		_, err := NewTokenBucket(nil, tt.capacity, tt.rate, tt.per)
+		assert.Error(t, err, "%+v", tt)
+	}
+}
+
+func TestGCRATestContainer(t *testing.T) {
+	ctx := context.Background()
+	client := newContainerClient(t)
+
+	limiter, err := NewGCRA(client, 10, time.Second, 3)
+	if err != nil {
+		t.Fatalf("Failed to create the GCRA limiter: %v", err)
+	}
+
+	t.Run("Weighted burst", func(t *testing.T) {
+		result, err := limiter.AllowN(ctx, "gcra", 3)
+		assert.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, int64(0), result.Remaining)
+
+		result, err = limiter.AllowN(ctx, "gcra", 2)
+		assert.NoError(t, err)
+		assert.False(t, result.Allowed)
+		assert.Greater(t, result.RetryAfter, 100*time.Millisecond)
+		assert.LessOrEqual(t, result.RetryAfter, 200*time.Millisecond)
+	})
+
+	t.Run("Cost above the burst", func(t *testing.T) {
+		_, err := limiter.AllowN(ctx, "gcra", 4)
+		assert.Error(t, err)
+	})
+
+	t.Run("Wait for a slot", func(t *testing.T) {
+		start := time.Now()
+		assert.NoError(t, limiter.Wait(ctx, "gcra"))
+		assert.Less(t, time.Since(start), 200*time.Millisecond)
+	})
+
+	t.Run("Wait is cancelled with the context", func(t *testing.T) {
+		result, err := limiter.AllowN(ctx, "gcra:cancel", 3)
+		assert.NoError(t, err)
+		assert.True(t, result.Allowed)
+
+		cancelCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+
+		assert.ErrorIs(t, limiter.Wait(cancelCtx, "gcra:cancel"), context.DeadlineExceeded)
+	})
+}
+
+func TestNewGCRAInvalid(t *testing.T) {
+	for _, tt := range []struct {
+		rate  int64
+		per   time.Duration
+		burst int64
+	}{
+		{rate: 0, per: time.Second, burst: 3},
+		{rate: 10, per: time.Second, burst: 0},
+		{rate: 10, per: 0, burst: 3},
+		{rate: 1000, per: time.Millisecond / 2, burst: 3},
+	} {
+		_, err := NewGCRA(nil, tt.rate, tt.per, tt.burst)
+		assert.Error(t, err, "%+v", tt)
+	}
+}
+
+func TestPolicyTestContainer(t *testing.T) {
+	ctx := context.Background()
+	client := newContainerClient(t)
+
+	policy := NewPolicy(client, PerSecond(3), PerMinute(5))
+
+	t.Run("Second tier trips first", func(t *testing.T) {
+		assert.Equal(t, 3, countAllowed(t, policy, "policy", 3))
+
+		result, err := policy.Allow(ctx, "policy")
+		assert.NoError(t, err)
+		assert.False(t, result.Allowed)
+		assert.Equal(t, "second", result.Tier)
+		assert.Equal(t, int64(3), result.Limit)
+		assert.LessOrEqual(t, result.RetryAfter, time.Second)
+
+		// The rejected request is not counted by the minute tier
+		assert.Equal(t, "3", client.Get(ctx, "{policy}:minute").Val())
+	})
+
+	t.Run("Minute tier trips next", func(t *testing.T) {
+		time.Sleep(time.Second)
+
+		result, err := policy.Allow(ctx, "policy")
+		assert.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, "minute", result.Tier)
+		assert.Equal(t, int64(1), result.Remaining)
+
+		assert.Equal(t, 1, countAllowed(t, policy, "policy", 2))
+
+		result, err = policy.Allow(ctx, "policy")
+		assert.NoError(t, err)
+		assert.False(t, result.Allowed)
+		assert.Equal(t, "minute", result.Tier)
+		assert.Greater(t, result.RetryAfter, 50*time.Second)
+
+		assert.Equal(t, "5", client.Get(ctx, "{policy}:minute").Val())
+		assert.Equal(t, "2", client.Get(ctx, "{policy}:second").Val())
+	})
+
+	t.Run("Tier key without expiration still limits", func(t *testing.T) {
+		assert.NoError(t, client.Set(ctx, "{stale}:second", 3, 0).Err())
+
+		result, err := policy.Allow(ctx, "stale")
+		assert.NoError(t, err)
+		assert.False(t, result.Allowed)
+		assert.Equal(t, "second", result.Tier)
+
+		ttl := client.PTTL(ctx, "{stale}:second").Val()
+		assert.Greater(t, ttl, time.Duration(0))
+		assert.LessOrEqual(t, ttl, time.Second)
+	})
+}
+
+// TestFailSafeLimiterTestContainer : Redis is stopped in the middle of the test
+func TestFailSafeLimiterTestContainer(t *testing.T) {
+	ctx := context.Background()
+
+	container, err := initDefaultRedisContainer(ctx)
+	if err != nil {
+		t.Fatalf("Failed to start redis container: %v", err)
+	}
+	defer container.Terminate(ctx)
+
+	client, err := newRedisClientContainer(ctx, container)
+	assert.NoError(t, err)
+	defer client.Close()
+
+	window := time.Minute
+	redisLimiter, err := NewRateLimiter(client, window, 4)
+	if err != nil {
+		t.Fatalf("Failed to create the rate limiter: %v", err)
+	}
+	openLimiter := NewFailSafeLimiter(redisLimiter, FailOpen, NewCircuitBreaker(2, time.Minute), nil)
+	closedLimiter := NewFailSafeLimiter(redisLimiter, FailClosed, NewCircuitBreaker(2, time.Minute), nil)
+	localLimiter := NewFailSafeLimiter(redisLimiter, FailLocal, NewCircuitBreaker(2, time.Minute), NewLocalLimiter(window, 4, 2))
+
+	t.Run("Redis is up", func(t *testing.T) {
+		for _, limiter := range []*FailSafeLimiter{openLimiter, closedLimiter, localLimiter} {
+			result, err := limiter.Allow(ctx, "failsafe")
+			assert.NoError(t, err)
+			assert.True(t, result.Allowed)
+			assert.False(t, result.Degraded)
+		}
+	})
+
+	err = container.Stop(ctx, nil)
+	assert.NoError(t, err)
+
+	t.Run("Fail open", func(t *testing.T) {
+		assert.Equal(t, 5, countAllowed(t, openLimiter, "failsafe", 5))
+		assert.Equal(t, BreakerOpen, openLimiter.BreakerState())
+	})
+
+	t.Run("Fail closed", func(t *testing.T) {
+		result, err := closedLimiter.Allow(ctx, "failsafe")
+		assert.NoError(t, err)
+		assert.False(t, result.Allowed)
+		assert.True(t, result.Degraded)
+		assert.Equal(t, time.Minute, result.RetryAfter)
+
+		assert.Equal(t, 0, countAllowed(t, closedLimiter, "failsafe", 4))
+		assert.Equal(t, BreakerOpen, closedLimiter.BreakerState())
+	})
+
+	t.Run("Fail local", func(t *testing.T) {
+		// Half of the limit for each of the two instances
+		assert.Equal(t, 2, countAllowed(t, localLimiter, "failsafe", 5))
+		assert.Equal(t, BreakerOpen, localLimiter.BreakerState())
+	})
+}
+
+func TestRuleStoreTestContainer(t *testing.T) {
+	ctx := context.Background()
+	client := newContainerClient(t)
+
+	store := NewRuleStore(client, "ratelimit:rules", 100*time.Millisecond)
+
+	t.Run("Invalid rules are refused", func(t *testing.T) {
+		assert.Error(t, store.Set(ctx, Rule{Match: "10.0.0.0/33", Action: RuleDeny}))
+		assert.Error(t, store.Set(ctx, Rule{Match: "partner", Action: RuleLimit}))
+		assert.Error(t, store.Set(ctx, Rule{Match: "partner", Action: "block"}))
+		assert.Error(t, store.Set(ctx, Rule{Match: "partner", Action: RuleLimit, Limit: 100, Window: time.Microsecond}))
+	})
+
+	t.Run("Set and list", func(t *testing.T) {
+		assert.NoError(t, store.Set(ctx, Rule{Match: "partner", Action: RuleLimit, Limit: 100, Window: time.Minute}))
+		assert.NoError(t, store.Set(ctx, Rule{Match: "10.0.0.0/8", Action: RuleAllow}))
+		assert.NoError(t, store.Set(ctx, Rule{Match: "10.1.0.0/16", Action: RuleDeny}))
+		assert.NoError(t, store.Set(ctx, Rule{Match: "192.168.1.70", Action: RuleDeny}))
+
+		rules, err := store.List(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []Rule{
+			{Match: "10.0.0.0/8", Action: RuleAllow},
+			{Match: "10.1.0.0/16", Action: RuleDeny},
+			{Match: "192.168.1.70", Action: RuleDeny},
+			{Match: HashAPIKey("partner"), Action: RuleLimit, Limit: 100, Window: time.Minute},
+		}, rules)
+
+		fields, err := client.HKeys(ctx, "ratelimit:rules").Result()
+		assert.NoError(t, err)
+		assert.NotContains(t, fields, "partner", "API keys should only be stored hashed")
+	})
+
+	t.Run("Match exact and most specific CIDR", func(t *testing.T) {
+		tests := []struct {
+			subject Subject
+			action  RuleAction
+			found   bool
+		}{
+			{subject: Subject{APIKey: "partner"}, action: RuleLimit, found: true},
+			{subject: Subject{IP: "192.168.1.70"}, action: RuleDeny, found: true},
+			{subject: Subject{IP: "10.2.3.4"}, action: RuleAllow, found: true},
+			{subject: Subject{IP: "10.1.3.4"}, action: RuleDeny, found: true},
+			{subject: Subject{IP: "192.168.1.71"}, found: false},
+			{subject: Subject{APIKey: "unknown"}, found: false},
+			// The rule of the key applies before the one of the IP, except a deny rule
+			{subject: Subject{IP: "10.2.3.4", APIKey: "partner"}, action: RuleLimit, found: true},
+			{subject: Subject{IP: "192.168.1.70", APIKey: "partner"}, action: RuleDeny, found: true},
+			// IP rules never match a key, and key rules never match an IP
+			{subject: Subject{APIKey: "10.2.3.4"}, found: false},
+			{subject: Subject{IP: "partner"}, found: false},
+		}
+
+		for _, tt := range tests {
+			rule, found, err := store.Match(ctx, tt.subject)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.found, found, tt.subject)
+			assert.Equal(t, tt.action, rule.Action, tt.subject)
+		}
+	})
+
+	t.Run("API key rules are deleted by key or by hash", func(t *testing.T) {
+		assert.NoError(t, store.Set(ctx, Rule{Match: "other", Action: RuleDeny}))
+		assert.NoError(t, store.Delete(ctx, "other"))
+		assert.NoError(t, store.Set(ctx, Rule{Match: "another", Action: RuleDeny}))
+		assert.NoError(t, store.Delete(ctx, HashAPIKey("another")))
+
+		rules, err := store.List(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, rules, 4)
+	})
+
+	t.Run("Deleted rules are forgotten after the cache TTL", func(t *testing.T) {
+		assert.NoError(t, store.Delete(ctx, "192.168.1.70"))
+
+		_, found, err := store.Match(ctx, Subject{IP: "192.168.1.70"})
+		assert.NoError(t, err)
+		assert.True(t, found, "The cached rule still applies")
+
+		time.Sleep(150 * time.Millisecond)
+
+		_, found, err = store.Match(ctx, Subject{IP: "192.168.1.70"})
+		assert.NoError(t, err)
+		assert.False(t, found)
+	})
+}
+
+func TestOverrideLimiterTestContainer(t *testing.T) {
+	ctx := context.Background()
+	client := newContainerClient(t)
+
+	store := NewRuleStore(client, "ratelimit:rules", time.Minute)
+	assert.NoError(t, store.Set(ctx, Rule{Match: "partner", Action: RuleLimit, Limit: 5, Window: time.Minute}))
+	assert.NoError(t, store.Set(ctx, Rule{Match: "10.0.0.0/8", Action: RuleAllow}))
+	assert.NoError(t, store.Set(ctx, Rule{Match: "192.168.1.80", Action: RuleDeny}))
+
+	defaultLimiter, err := NewRateLimiter(client, time.Minute, 2)
+	if err != nil {
+		t.Fatalf("Failed to create the rate limiter: %v", err)
+	}
+	limiter := NewOverrideLimiter(client, defaultLimiter, store)
+
+	allowedSubject := func(subject Subject, requests int) int {
+		allowed := 0
+		for range requests {
+			result, err := limiter.AllowSubject(ctx, subject, "override:"+subject.IP+subject.APIKey)
+			assert.NoError(t, err)
+			if result.Allowed {
+				allowed++
+			}
+		}
+		return allowed
+	}
+
+	assert.Equal(t, 2, allowedSubject(Subject{IP: "192.168.1.81"}, 6), "Default limit")
+	assert.Equal(t, 5, allowedSubject(Subject{IP: "192.168.1.82", APIKey: "partner"}, 6), "Raised limit")
+	assert.Equal(t, 6, allowedSubject(Subject{IP: "10.20.30.40"}, 6), "Exempted network")
+
+	for _, subject := range []Subject{{IP: "192.168.1.80"}, {IP: "192.168.1.80", APIKey: "partner"}} {
+		result, err := limiter.AllowSubject(ctx, subject, "override:192.168.1.80")
+		assert.NoError(t, err)
+		assert.False(t, result.Allowed)
+		assert.True(t, result.Denied)
+	}
+}
@@ -0,0 +1,344 @@
+package ratelimit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/netip"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type RuleAction string
+
+const (
+	// RuleAllow : The subject is never limited
+	RuleAllow RuleAction = "allow"
+	// RuleDeny : Every request of the subject is rejected
+	RuleDeny RuleAction = "deny"
+	// RuleLimit : The subject has its own limit instead of the default one
+	RuleLimit RuleAction = "limit"
+)
+
+// Subject : Who sends the request, as far as the caller can trust it
+// IP must be the peer address or the one given by a trusted proxy, APIKey is only set once the key is authenticated
+type Subject struct {
+	IP     string
+	APIKey string
+}
+
+// Rule : Override of the limit for the subjects matching Match
+// An IP or a CIDR such as 10.0.0.0/8 matches the IP of the subject, anything else matches its API key
+// API keys are credentials, they are stored and listed as their HashAPIKey
+type Rule struct {
+	Match  string
+	Action RuleAction
+	// Limit and Window are only used by RuleLimit
+	Limit  int64
+	Window time.Duration
+}
+
+func (r Rule) Validate() error {
+	if r.Match == "" {
+		return fmt.Errorf("rule without match")
+	}
+
+	if strings.Contains(r.Match, "/") {
+		if _, err := netip.ParsePrefix(r.Match); err != nil {
+			return fmt.Errorf("invalid CIDR %q: %w", r.Match, err)
+		}
+	}
+
+	switch r.Action {
+	case RuleAllow, RuleDeny:
+		return nil
+	case RuleLimit:
+		if r.Limit <= 0 || r.Window <= 0 {
+			return fmt.Errorf("limit rule %q needs a positive limit and window", r.Match)
+		}
+		// The window is stored in milliseconds
+		if r.Window < time.Millisecond {
+			return fmt.Errorf("limit rule %q needs a window of at least 1ms", r.Match)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown action %q, expected allow, deny or limit", r.Action)
+	}
+}
+
+// storedRule : Value of the hash field, the field is the match
+type storedRule struct {
+	Action   RuleAction `json:"action"`
+	Limit    int64      `json:"limit,omitempty"`
+	WindowMs int64      `json:"windowMs,omitempty"`
+}
+
+// RuleStore : Rules are kept in a Redis hash, shared by every instance
+// Matching uses a local copy refreshed every cacheTTL, changes take up to cacheTTL to apply
+// One request refreshes the copy while the others keep matching the previous one
+// Before the first load there is no previous copy, the requests wait for the one loading it
+type RuleStore struct {
+	client   *redis.Client
+	hash     string
+	cacheTTL time.Duration
+
+	rules      atomic.Pointer[ruleSet]
+	refreshing atomic.Bool
+
+	mu        sync.Mutex
+	firstLoad *ruleLoad
+}
+
+// ruleLoad : First load of the rules, done is closed once rules or err is set
+type ruleLoad struct {
+	done  chan struct{}
+	rules *ruleSet
+	err   error
+}
+
+// ruleSet : Copy of the rules, never changed once stored in the RuleStore
+type ruleSet struct {
+	keys     map[string]Rule
+	networks []networkRule
+	loadedAt time.Time
+}
+
+type networkRule struct {
+	prefix netip.Prefix
+	rule   Rule
+}
+
+// HashAPIKey : SHA-256 of the key in hex, Redis never holds the key itself
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// storedMatch : IPs and CIDRs are kept as they are, API keys are hashed
+func storedMatch(match string) string {
+	if _, err := parseNetwork(match); err == nil {
+		return match
+	}
+	return HashAPIKey(match)
+}
+
+func NewRuleStore(client *redis.Client, hash string, cacheTTL time.Duration) *RuleStore {
+	return &RuleStore{
+		client:   client,
+		hash:     hash,
+		cacheTTL: cacheTTL,
+	}
+}
+
+func (s *RuleStore) Set(ctx context.Context, rule Rule) error {
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+
+	value, err := json.Marshal(storedRule{
+		Action:   rule.Action,
+		Limit:    rule.Limit,
+		WindowMs: rule.Window.Milliseconds(),
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.client.HSet(ctx, s.hash, storedMatch(rule.Match), value).Err()
+}
+
+// Delete : match is an IP, a CIDR, an API key or the hash of a key given by List
+func (s *RuleStore) Delete(ctx context.Context, match string) error {
+	return s.client.HDel(ctx, s.hash, match, storedMatch(match)).Err()
+}
+
+// List : Rules read from Redis, sorted by match, the match of an API key is its hash
+// Rules that can not be decoded are skipped
+func (s *RuleStore) List(ctx context.Context) ([]Rule, error) {
+	values, err := s.client.HGetAll(ctx, s.hash).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	rules := make([]Rule, 0, len(values))
+	for match, value := range values {
+		var stored storedRule
+		if err := json.Unmarshal([]byte(value), &stored); err != nil {
+			continue
+		}
+
+		rule := Rule{
+			Match:  match,
+			Action: stored.Action,
+			Limit:  stored.Limit,
+			Window: time.Duration(stored.WindowMs) * time.Millisecond,
+		}
+		if rule.Validate() != nil {
+			continue
+		}
+
+		rules = append(rules, rule)
+	}
+
+	sort.Slice(rules, func(i, j int) bool { return rules[i].Match < rules[j].Match })
+	return rules, nil
+}
+
+// Match : The rule of the API key or else the most specific one containing the IP, a deny rule of either wins
+// Sending an API key never escapes the deny rule of the IP, and an IP never matches the rule of a key
+// When the rules can not be refreshed the previous ones are kept, an error is only returned when the first load fails
+func (s *RuleStore) Match(ctx context.Context, subject Subject) (Rule, bool, error) {
+	rules, err := s.load(ctx)
+	if err != nil {
+		return Rule{}, false, err
+	}
+
+	ipRule, ipFound := rules.matchIP(subject.IP)
+	keyRule, keyFound := rules.keys[HashAPIKey(subject.APIKey)]
+	if subject.APIKey == "" {
+		keyFound = false
+	}
+
+	switch {
+	case ipFound && ipRule.Action == RuleDeny:
+		return ipRule, true, nil
+	case keyFound:
+		return keyRule, true, nil
+	default:
+		return ipRule, ipFound, nil
+	}
+}
+
+// load : The rules, refreshed by the caller when they are older than cacheTTL and nobody else refreshes them
+// Once loaded, Redis is never called while other requests wait for it
+// A failed refresh keeps the previous rules and their age, the next request tries again
+func (s *RuleStore) load(ctx context.Context) (*ruleSet, error) {
+	rules := s.rules.Load()
+	if rules == nil {
+		return s.loadFirst(ctx)
+	}
+
+	if time.Since(rules.loadedAt) < s.cacheTTL || !s.refreshing.CompareAndSwap(false, true) {
+		return rules, nil
+	}
+	defer s.refreshing.Store(false)
+
+	refreshed, err := s.refresh(ctx)
+	if err != nil {
+		return rules, nil
+	}
+
+	s.rules.Store(refreshed)
+	return refreshed, nil
+}
+
+// loadFirst : Concurrent requests share the load in flight instead of failing
+// A waiting request only gives up with its own context, or with the error of the load
+func (s *RuleStore) loadFirst(ctx context.Context) (*ruleSet, error) {
+	s.mu.Lock()
+	if rules := s.rules.Load(); rules != nil {
+		s.mu.Unlock()
+		return rules, nil
+	}
+
+	call := s.firstLoad
+	if call != nil {
+		s.mu.Unlock()
+
+		select {
+		case <-call.done:
+			// The context of the loading request says nothing about Redis, this one tries again
+			if errors.Is(call.err, context.Canceled) || errors.Is(call.err, context.DeadlineExceeded) {
+				return s.loadFirst(ctx)
+			}
+			return call.rules, call.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	call = &ruleLoad{done: make(chan struct{})}
+	s.firstLoad = call
+	s.mu.Unlock()
+
+	call.rules, call.err = s.refresh(ctx)
+
+	s.mu.Lock()
+	if call.err == nil {
+		s.rules.Store(call.rules)
+	}
+	s.firstLoad = nil
+	s.mu.Unlock()
+	close(call.done)
+
+	return call.rules, call.err
+}
+
+func (s *RuleStore) refresh(ctx context.Context) (*ruleSet, error) {
+	rules, err := s.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return newRuleSet(rules), nil
+}
+
+func newRuleSet(rules []Rule) *ruleSet {
+	keys := make(map[string]Rule, len(rules))
+	networks := make([]networkRule, 0)
+	for _, rule := range rules {
+		prefix, err := parseNetwork(rule.Match)
+		if err != nil {
+			keys[rule.Match] = rule
+			continue
+		}
+		networks = append(networks, networkRule{prefix: prefix, rule: rule})
+	}
+
+	sort.SliceStable(networks, func(i, j int) bool { return networks[i].prefix.Bits() > networks[j].prefix.Bits() })
+
+	return &ruleSet{
+		keys:     keys,
+		networks: networks,
+		loadedAt: time.Now(),
+	}
+}
+
+// matchIP : networks are sorted from the most specific, a single IP being the most specific of all
+func (r *ruleSet) matchIP(ip string) (Rule, bool) {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return Rule{}, false
+	}
+
+	for _, network := range r.networks {
+		if network.prefix.Contains(addr.Unmap()) {
+			return network.rule, true
+		}
+	}
+
+	return Rule{}, false
+}
+
+// parseNetwork : A single IP is the network of its own address
+func parseNetwork(match string) (netip.Prefix, error) {
+	if addr, err := netip.ParseAddr(match); err == nil {
+		addr = addr.Unmap()
+		return netip.PrefixFrom(addr, addr.BitLen()), nil
+	}
+
+	prefix, err := netip.ParsePrefix(match)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+
+	return prefix.Masked(), nil
+}
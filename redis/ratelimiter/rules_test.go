@@ -0,0 +1,92 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRuleValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  Rule
+		valid bool
+	}{
+		{name: "Allow IP", rule: Rule{Match: "10.0.0.1", Action: RuleAllow}, valid: true},
+		{name: "Deny CIDR", rule: Rule{Match: "10.0.0.0/8", Action: RuleDeny}, valid: true},
+		{name: "Limit key", rule: Rule{Match: "partner", Action: RuleLimit, Limit: 10, Window: time.Millisecond}, valid: true},
+		{name: "Without match", rule: Rule{Action: RuleAllow}},
+		{name: "Invalid CIDR", rule: Rule{Match: "10.0.0.0/33", Action: RuleDeny}},
+		{name: "Unknown action", rule: Rule{Match: "partner", Action: "block"}},
+		{name: "Limit without window", rule: Rule{Match: "partner", Action: RuleLimit, Limit: 10}},
+		{name: "Sub-millisecond window", rule: Rule{Match: "partner", Action: RuleLimit, Limit: 10, Window: time.Microsecond}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rule.Validate()
+			assert.Equal(t, tt.valid, err == nil, err)
+		})
+	}
+}
+
+func TestRuleStoreRedisDown(t *testing.T) {
+	ctx := context.Background()
+
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1})
+	defer client.Close()
+
+	store := NewRuleStore(client, "ratelimit:rules", time.Millisecond)
+
+	_, _, err := store.Match(ctx, Subject{IP: "10.0.0.1"})
+	assert.Error(t, err, "Nothing can be matched before the first load")
+
+	// Loaded before Redis went down
+	loadedAt := time.Now().Add(-time.Hour)
+	rules := newRuleSet([]Rule{{Match: "10.0.0.0/8", Action: RuleDeny}})
+	rules.loadedAt = loadedAt
+	store.rules.Store(rules)
+
+	rule, found, err := store.Match(ctx, Subject{IP: "10.0.0.1", APIKey: "partner"})
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, RuleDeny, rule.Action)
+	assert.Equal(t, loadedAt, store.rules.Load().loadedAt, "The next request should try Redis again")
+
+	// Another request is refreshing, the current rules are used without waiting
+	store.refreshing.Store(true)
+	_, found, err = store.Match(ctx, Subject{IP: "10.0.0.1"})
+	assert.NoError(t, err)
+	assert.True(t, found)
+}
+
+func TestRuleStoreWaitsForTheFirstLoad(t *testing.T) {
+	ctx := context.Background()
+
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1})
+	defer client.Close()
+
+	store := NewRuleStore(client, "ratelimit:rules", time.Minute)
+
+	// Another request is loading the rules
+	call := &ruleLoad{done: make(chan struct{})}
+	store.firstLoad = call
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, _, err := store.Match(timeoutCtx, Subject{IP: "10.0.0.1"})
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "Only the context of the waiting request ends the wait")
+
+	go func() {
+		call.rules = newRuleSet([]Rule{{Match: "10.0.0.0/8", Action: RuleDeny}})
+		close(call.done)
+	}()
+
+	rule, found, err := store.Match(ctx, Subject{IP: "10.0.0.1"})
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, RuleDeny, rule.Action)
+}
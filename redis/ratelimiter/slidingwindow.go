@@ -0,0 +1,151 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// SlidingWindowLog : Every accepted request is kept in a sorted set for the duration of the window
+// Exact, no burst at the window boundary, but the memory grows with the limit
+type SlidingWindowLog struct {
+	client *redis.Client
+	window time.Duration
+	limit  int64
+}
+
+// NewSlidingWindowLog : limit must be positive, window at least 1ms
+func NewSlidingWindowLog(client *redis.Client, window time.Duration, limit int64) (*SlidingWindowLog, error) {
+	if err := validateWindow(limit, window); err != nil {
+		return nil, err
+	}
+
+	return &SlidingWindowLog{
+		client: client,
+		window: window,
+		limit:  limit,
+	}, nil
+}
+
+// slidingWindowLogScript : Scores are the Redis time in microseconds, rejected requests are not logged
+var slidingWindowLogScript = redis.NewScript(`
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000000 + tonumber(time[2])
+local window = tonumber(ARGV[1])
+local limit = tonumber(ARGV[2])
+local window_ms = math.ceil(window / 1000)
+
+redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", now - window)
+local count = redis.call("ZCARD", KEYS[1])
+
+if count < limit then
+	redis.call("ZADD", KEYS[1], now, ARGV[3])
+	redis.call("PEXPIRE", KEYS[1], window_ms)
+	return {1, limit - count - 1, 0, window_ms}
+end
+
+local oldest = redis.call("ZRANGE", KEYS[1], 0, 0, "WITHSCORES")
+local newest = redis.call("ZRANGE", KEYS[1], -1, -1, "WITHSCORES")
+if oldest[2] == nil then
+	return {0, 0, window_ms, window_ms}
+end
+
+return {0, 0, math.ceil((tonumber(oldest[2]) + window - now) / 1000), math.ceil((tonumber(newest[2]) + window - now) / 1000)}
+`)
+
+func (s *SlidingWindowLog) Allow(ctx context.Context, key string) (Result, error) {
+	values, err := slidingWindowLogScript.Run(ctx, s.client, []string{key},
+		s.window.Microseconds(),
+		s.limit,
+		uuid.NewString(),
+	).Int64Slice()
+	if err != nil {
+		return Result{}, err
+	}
+
+	return newScriptResult(values, s.limit), nil
+}
+
+// SlidingWindowCounter : Approximation of the sliding window with the counters of the current and previous fixed windows
+// The previous counter is weighted by the part of the previous window still inside the sliding window
+// Two counters per key whatever the limit, the error is small when the traffic is regular
+type SlidingWindowCounter struct {
+	client *redis.Client
+	window time.Duration
+	limit  int64
+}
+
+// NewSlidingWindowCounter : limit must be positive, window at least 1ms
+func NewSlidingWindowCounter(client *redis.Client, window time.Duration, limit int64) (*SlidingWindowCounter, error) {
+	if err := validateWindow(limit, window); err != nil {
+		return nil, err
+	}
+
+	return &SlidingWindowCounter{
+		client: client,
+		window: window,
+		limit:  limit,
+	}, nil
+}
+
+// slidingWindowCounterScript : Windows are aligned on the Redis clock, the hash keeps the index of the current window
+var slidingWindowCounterScript = redis.NewScript(`
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000000 + tonumber(time[2])
+local window = tonumber(ARGV[1])
+local limit = tonumber(ARGV[2])
+
+local index = math.floor(now / window)
+local elapsed = (now - index * window) / window
+
+local state = redis.call("HMGET", KEYS[1], "window", "current", "previous")
+local stored = tonumber(state[1]) or index
+local current = tonumber(state[2]) or 0
+local previous = tonumber(state[3]) or 0
+if stored == index - 1 then
+	previous = current
+	current = 0
+elseif stored < index - 1 then
+	previous = 0
+	current = 0
+end
+
+local estimate = previous * (1 - elapsed) + current
+
+if estimate + 1 <= limit then
+	current = current + 1
+	redis.call("HSET", KEYS[1], "window", index, "current", current, "previous", previous)
+	redis.call("PEXPIRE", KEYS[1], math.ceil(2 * window / 1000))
+	return {1, math.floor(limit - estimate - 1), 0, math.ceil(((index + 2) * window - now) / 1000)}
+end
+
+local reset = (index + 2) * window - now
+if current == 0 then
+	reset = (index + 1) * window - now
+end
+
+-- Wait for the weight of the previous window to drop, or for the next window when the current one is full
+local retry
+local headroom = limit - 1 - current
+if headroom >= 0 then
+	retry = (1 - headroom / previous - elapsed) * window
+else
+	retry = (index + 1) * window - now + (1 - (limit - 1) / current) * window
+end
+
+return {0, 0, math.ceil(retry / 1000), math.ceil(reset / 1000)}
+`)
+
+func (s *SlidingWindowCounter) Allow(ctx context.Context, key string) (Result, error) {
+	values, err := slidingWindowCounterScript.Run(ctx, s.client, []string{key},
+		s.window.Microseconds(),
+		s.limit,
+	).Int64Slice()
+	if err != nil {
+		return Result{}, err
+	}
+
+	return newScriptResult(values, s.limit), nil
+}
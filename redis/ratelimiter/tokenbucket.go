@@ -0,0 +1,77 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// TokenBucket : The bucket holds up to capacity tokens and is refilled with rate tokens every period
+// A full bucket accepts a burst of capacity requests, then the refill rate applies
+type TokenBucket struct {
+	client   *redis.Client
+	capacity int64
+	// interval is the time to refill one token
+	interval time.Duration
+}
+
+// NewTokenBucket : capacity and rate must be positive, and a token can not be refilled in less than 1µs
+// The script counts in microseconds
+func NewTokenBucket(client *redis.Client, capacity int64, rate int64, per time.Duration) (*TokenBucket, error) {
+	if capacity <= 0 || rate <= 0 {
+		return nil, fmt.Errorf("capacity %d and rate %d must be positive", capacity, rate)
+	}
+
+	interval := per / time.Duration(rate)
+	if interval < time.Microsecond {
+		return nil, fmt.Errorf("refill interval %s of %d tokens every %s is below 1µs", interval, rate, per)
+	}
+
+	return &TokenBucket{
+		client:   client,
+		capacity: capacity,
+		interval: interval,
+	}, nil
+}
+
+// tokenBucketScript : Tokens are refilled lazily from the last update, the key expires once the bucket is full again
+var tokenBucketScript = redis.NewScript(`
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000000 + tonumber(time[2])
+local capacity = tonumber(ARGV[1])
+local interval = tonumber(ARGV[2])
+
+local state = redis.call("HMGET", KEYS[1], "tokens", "updated")
+local tokens = tonumber(state[1]) or capacity
+local updated = tonumber(state[2]) or now
+tokens = math.min(capacity, tokens + math.max(0, now - updated) / interval)
+
+local allowed = 0
+local retry = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	retry = (1 - tokens) * interval
+end
+
+local reset = (capacity - tokens) * interval
+redis.call("HSET", KEYS[1], "tokens", tokens, "updated", now)
+redis.call("PEXPIRE", KEYS[1], math.max(1, math.ceil(reset / 1000)))
+
+return {allowed, math.floor(tokens), math.ceil(retry / 1000), math.ceil(reset / 1000)}
+`)
+
+func (t *TokenBucket) Allow(ctx context.Context, key string) (Result, error) {
+	values, err := tokenBucketScript.Run(ctx, t.client, []string{key},
+		t.capacity,
+		t.interval.Microseconds(),
+	).Int64Slice()
+	if err != nil {
+		return Result{}, err
+	}
+
+	return newScriptResult(values, t.capacity), nil
+}
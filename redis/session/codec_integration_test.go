@@ -0,0 +1,83 @@
+package session
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sessions "github.com/christapa/testContainers/session"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSession(t *testing.T) *sessions.Session {
+	t.Helper()
+
+	session := &sessions.Session{
+		SessionID: uuid.New().String(),
+		Username:  "test",
+		Roles:     []string{"admin"},
+		CSRFToken: uuid.New().String(),
+		Flashes:   []string{"Link created"},
+		// Codecs do not all keep the location
+		LastActivity: time.Unix(1700000000, 0).UTC(),
+	}
+	require.NoError(t, sessions.SetAttribute(session, "theme", "dark"))
+
+	return session
+}
+
+func TestRedisSessionRepository_Codecs(t *testing.T) {
+	ctx := context.Background()
+
+	codecs := map[string]sessions.Codec{
+		"JSON":    sessions.JSONCodec,
+		"Gob":     sessions.GobCodec,
+		"Msgpack": sessions.MsgpackCodec,
+	}
+
+	for name, codec := range codecs {
+		t.Run(name, func(t *testing.T) {
+			sessionRedisRepository := newTestRepository(t, 10*time.Minute)
+			sessionRedisRepository.Codec = codec
+			session := newTestSession(t)
+
+			err := sessionRedisRepository.Save(ctx, session)
+			require.NoError(t, err)
+
+			savedSession, err := sessionRedisRepository.Get(ctx, session.SessionID)
+			require.NoError(t, err)
+			assert.True(t, session.LastActivity.Equal(savedSession.LastActivity))
+			savedSession.LastActivity = session.LastActivity
+			assert.Zero(t, session.Version)
+			session.Version = sessions.CurrentVersion
+			assert.Equal(t, session, savedSession)
+
+			// A repository using another codec still reads the session
+			otherRepository := newTestRepository(t, 10*time.Minute)
+			savedSession, err = otherRepository.Get(ctx, session.SessionID)
+			require.NoError(t, err)
+			assert.Equal(t, session.Username, savedSession.Username)
+		})
+	}
+}
+
+func TestRedisSessionRepository_UpgradeOnRead(t *testing.T) {
+	ctx := context.Background()
+	sessionID := uuid.New().String()
+
+	// sessions.Session saved before the version field
+	err := redisClient.Set(ctx, sessionKey(sessionID), `{"SessionID":"`+sessionID+`","Username":"test"}`, time.Minute).Err()
+	require.NoError(t, err)
+
+	sessionRedisRepository := newTestRepository(t, 10*time.Minute)
+	savedSession, err := sessionRedisRepository.Get(ctx, sessionID)
+	require.NoError(t, err)
+
+	assert.Equal(t, &sessions.Session{
+		Version:   sessions.CurrentVersion,
+		SessionID: sessionID,
+		Username:  "test",
+	}, savedSession)
+}
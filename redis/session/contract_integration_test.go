@@ -0,0 +1,20 @@
+package session_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/christapa/testContainers/redis/session"
+	sessions "github.com/christapa/testContainers/session"
+	"github.com/christapa/testContainers/session/sessiontest"
+)
+
+func TestRedisSessionRepository_Contract(t *testing.T) {
+	sessiontest.Run(t, func(t *testing.T, ttl time.Duration) sessions.SessionRepository {
+		repository, err := session.NewRedisSessionRepository(session.ContainerClient(), ttl)
+		if err != nil {
+			t.Fatalf("Failed to create the session repository: %v", err)
+		}
+		return repository
+	})
+}
@@ -0,0 +1,10 @@
+package session
+
+import (
+	redis "github.com/redis/go-redis/v9"
+)
+
+// ContainerClient : Client of the container started by TestMain, for the external tests
+func ContainerClient() *redis.Client {
+	return redisClient
+}
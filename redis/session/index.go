@@ -0,0 +1,68 @@
+package session
+
+import (
+	sessions "github.com/christapa/testContainers/session"
+	redis "github.com/redis/go-redis/v9"
+)
+
+func sessionKey(sessionID string) string {
+	return "session:" + sessions.HashID(sessionID)
+}
+
+// userIndexKey : Sorted set of the session keys of a user, scored by the expiry of each session in ms
+// The set expires with the last of its sessions, expired members are pruned on write and on list
+func userIndexKey(username string) string {
+	return "session:user:" + username
+}
+
+// saveScript : KEYS are the session and its user index, ARGV the value, the TTL in ms and the session key
+var saveScript = redis.NewScript(`
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+local ttl = tonumber(ARGV[2])
+
+redis.call("SET", KEYS[1], ARGV[1], "PX", ttl)
+redis.call("ZREMRANGEBYSCORE", KEYS[2], "-inf", now)
+redis.call("ZADD", KEYS[2], now + ttl, ARGV[3])
+if redis.call("PTTL", KEYS[2]) < ttl then
+	redis.call("PEXPIRE", KEYS[2], ttl)
+end
+return 1
+`)
+
+// touchScript : ARGV are the TTL in ms and the session key, returns 0 when the session has expired
+var touchScript = redis.NewScript(`
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+local ttl = tonumber(ARGV[1])
+
+if redis.call("PEXPIRE", KEYS[1], ttl) == 0 then
+	redis.call("ZREM", KEYS[2], ARGV[2])
+	return 0
+end
+redis.call("ZADD", KEYS[2], now + ttl, ARGV[2])
+if redis.call("PTTL", KEYS[2]) < ttl then
+	redis.call("PEXPIRE", KEYS[2], ttl)
+end
+return 1
+`)
+
+// listScript : Prune the expired members of the index and return the others
+var listScript = redis.NewScript(`
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", now)
+return redis.call("ZRANGE", KEYS[1], 0, -1)
+`)
+
+// revokeScript : The session keys come from the index, so the script is not compatible with Redis Cluster
+var revokeScript = redis.NewScript(`
+local sessionKeys = redis.call("ZRANGE", KEYS[1], 0, -1)
+local deleted = 0
+for _, sessionKey in ipairs(sessionKeys) do
+	deleted = deleted + redis.call("DEL", sessionKey)
+end
+redis.call("DEL", KEYS[1])
+return deleted
+`)
@@ -0,0 +1,291 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/christapa/testContainers/session"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	echoMiddleware "github.com/labstack/echo/v4/middleware"
+)
+
+// contextKey : The *state of the request in the echo.Context
+const contextKey = "session"
+
+type Config struct {
+	Skipper    echoMiddleware.Skipper
+	Repository session.SessionRepository
+	// Secret signs the cookie with HMAC-SHA256, it is required
+	Secret []byte
+
+	// CookieName defaults to "session", CookiePath to "/" and SameSite to http.SameSiteLaxMode
+	CookieName   string
+	CookiePath   string
+	CookieDomain string
+	// Insecure lets the browser send the cookie over plain HTTP, only for local development
+	// The cookie is Secure unless it is set
+	Insecure bool
+	SameSite http.SameSite
+	// MaxAge of the cookie, it should be the TTL of the repository, 0 for a cookie that ends with the browser
+	MaxAge time.Duration
+}
+
+var DefaultConfig = Config{
+	Skipper:    echoMiddleware.DefaultSkipper,
+	CookieName: "session",
+	CookiePath: "/",
+	SameSite:   http.SameSiteLaxMode,
+}
+
+// state : Session of one request
+type state struct {
+	session *session.Session
+	// loadedID is the ID the session was loaded with, empty for a new session
+	loadedID string
+	// loaded is the value of the session when it was loaded, an unchanged session is only touched
+	loaded    []byte
+	destroyed bool
+	persisted bool
+}
+
+// New : maxAge of the cookie should be the TTL of the repository, 0 for a cookie that ends with the browser
+func New(repository session.SessionRepository, maxAge time.Duration, secret []byte) echo.MiddlewareFunc {
+	config := DefaultConfig
+	config.Repository = repository
+	config.MaxAge = maxAge
+	config.Secret = secret
+	return NewWithConfig(config)
+}
+
+// NewWithConfig : Load the session of the signed cookie and save it before the response is written
+// Panics when the repository or the secret is missing
+func NewWithConfig(config Config) echo.MiddlewareFunc {
+	if config.Repository == nil {
+		panic("session middleware requires a repository")
+	}
+	if len(config.Secret) == 0 {
+		panic("session middleware requires a secret")
+	}
+	if config.Skipper == nil {
+		config.Skipper = DefaultConfig.Skipper
+	}
+	if config.CookieName == "" {
+		config.CookieName = DefaultConfig.CookieName
+	}
+	if config.CookiePath == "" {
+		config.CookiePath = DefaultConfig.CookiePath
+	}
+	if config.SameSite == 0 {
+		config.SameSite = DefaultConfig.SameSite
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+
+			requestState, err := config.load(c)
+			if err != nil {
+				return err
+			}
+			c.Set(contextKey, requestState)
+
+			c.Response().Before(func() {
+				if err := config.persist(c, requestState); err != nil {
+					c.Logger().Errorf("Failed to save session: %v", err)
+				}
+			})
+
+			err = next(c)
+
+			// Nothing was written, the hook did not run
+			if !requestState.persisted && !c.Response().Committed {
+				if persistErr := config.persist(c, requestState); persistErr != nil {
+					return persistErr
+				}
+			}
+
+			return err
+		}
+	}
+}
+
+// Get : Session of the request, nil when the client has none
+func Get(c echo.Context) *session.Session {
+	requestState, ok := c.Get(contextKey).(*state)
+	if !ok || requestState.destroyed {
+		return nil
+	}
+
+	return requestState.session
+}
+
+// Start : Replace the session of the request by a new one of the user, such as on login
+// The previous session is deleted, so an ID set by an attacker before the login is never authenticated
+func Start(c echo.Context, username string) *session.Session {
+	requestState := mustState(c)
+	requestState.session = &session.Session{
+		SessionID: newSessionID(),
+		Username:  username,
+	}
+	requestState.loaded = nil
+	requestState.destroyed = false
+	return requestState.session
+}
+
+// Renew : Give a new ID to the session of the request, to call when its privileges change
+func Renew(c echo.Context) {
+	requestState := mustState(c)
+	if requestState.session == nil || requestState.destroyed {
+		return
+	}
+
+	requestState.session.SessionID = newSessionID()
+	requestState.loaded = nil
+}
+
+// Destroy : Delete the session of the request and expire its cookie, such as on logout
+func Destroy(c echo.Context) {
+	mustState(c).destroyed = true
+}
+
+func mustState(c echo.Context) *state {
+	requestState, ok := c.Get(contextKey).(*state)
+	if !ok {
+		panic("session middleware is not registered")
+	}
+	return requestState
+}
+
+func newSessionID() string {
+	return uuid.New().String()
+}
+
+// load : A missing, forged or expired cookie gives a request without session
+func (config Config) load(c echo.Context) (*state, error) {
+	requestState := &state{}
+
+	cookie, err := c.Cookie(config.CookieName)
+	if err != nil {
+		return requestState, nil
+	}
+
+	sessionID, ok := config.verify(cookie.Value)
+	if !ok {
+		return requestState, nil
+	}
+
+	loadedSession, err := config.Repository.Get(c.Request().Context(), sessionID)
+	if errors.Is(err, session.ErrNotFound) || errors.Is(err, session.ErrInvalidSeal) {
+		return requestState, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	loaded, err := loadedSession.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	requestState.session = loadedSession
+	requestState.loadedID = sessionID
+	requestState.loaded = loaded
+	return requestState, nil
+}
+
+// persist : Save a changed session, touch an unchanged one, and set the cookie
+func (config Config) persist(c echo.Context, requestState *state) error {
+	if requestState.persisted {
+		return nil
+	}
+	requestState.persisted = true
+
+	ctx := c.Request().Context()
+	current := requestState.session
+
+	if requestState.destroyed || current == nil {
+		if requestState.loadedID == "" {
+			return nil
+		}
+		config.expireCookie(c)
+		return config.Repository.Delete(ctx, requestState.loadedID)
+	}
+
+	if current.SessionID != requestState.loadedID && requestState.loadedID != "" {
+		if err := config.Repository.Delete(ctx, requestState.loadedID); err != nil {
+			return err
+		}
+	}
+
+	value, err := current.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	if current.SessionID == requestState.loadedID && bytes.Equal(value, requestState.loaded) {
+		err = config.Repository.Touch(ctx, current.SessionID)
+		// Expired during the request
+		if errors.Is(err, session.ErrNotFound) {
+			err = config.Repository.Save(ctx, current)
+		}
+	} else {
+		err = config.Repository.Save(ctx, current)
+	}
+	if err != nil {
+		return err
+	}
+
+	config.setCookie(c, config.sign(current.SessionID), int(config.MaxAge.Seconds()))
+	return nil
+}
+
+func (config Config) expireCookie(c echo.Context) {
+	config.setCookie(c, "", -1)
+}
+
+func (config Config) setCookie(c echo.Context, value string, maxAge int) {
+	c.SetCookie(&http.Cookie{
+		Name:     config.CookieName,
+		Value:    value,
+		Path:     config.CookiePath,
+		Domain:   config.CookieDomain,
+		MaxAge:   maxAge,
+		Secure:   !config.Insecure,
+		HttpOnly: true,
+		SameSite: config.SameSite,
+	})
+}
+
+// sign : The cookie value is the session ID followed by its HMAC
+func (config Config) sign(sessionID string) string {
+	return sessionID + "." + base64.RawURLEncoding.EncodeToString(config.mac(sessionID))
+}
+
+func (config Config) verify(value string) (string, bool) {
+	sessionID, signature, found := strings.Cut(value, ".")
+	if !found || sessionID == "" {
+		return "", false
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil {
+		return "", false
+	}
+
+	return sessionID, hmac.Equal(decoded, config.mac(sessionID))
+}
+
+func (config Config) mac(sessionID string) []byte {
+	mac := hmac.New(sha256.New, config.Secret)
+	mac.Write([]byte(sessionID))
+	return mac.Sum(nil)
+}
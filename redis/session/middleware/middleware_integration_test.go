@@ -0,0 +1,241 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/christapa/testContainers/redis/session"
+	"github.com/labstack/echo/v4"
+	redis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	testcontainers "github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+var testSecret = []byte("0123456789abcdef0123456789abcdef")
+
+func newContainerClient(t *testing.T) *redis.Client {
+	t.Helper()
+	ctx := context.Background()
+
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        "docker.io/redis:7",
+			ExposedPorts: []string{"6379/tcp"},
+			WaitingFor:   wait.ForLog("* Ready to accept connections"),
+		},
+		Started: true,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = container.Terminate(ctx) })
+
+	endpoint, err := container.Endpoint(ctx, "")
+	require.NoError(t, err)
+
+	client := redis.NewClient(&redis.Options{Addr: endpoint})
+	t.Cleanup(func() { _ = client.Close() })
+
+	return client
+}
+
+func TestSignedCookie(t *testing.T) {
+	config := Config{Secret: testSecret}
+	value := config.sign("session-id")
+
+	t.Run("Valid signature", func(t *testing.T) {
+		sessionID, ok := config.verify(value)
+		assert.True(t, ok)
+		assert.Equal(t, "session-id", sessionID)
+	})
+
+	t.Run("Forged session ID", func(t *testing.T) {
+		_, ok := config.verify("other-id" + value[len("session-id"):])
+		assert.False(t, ok)
+	})
+
+	t.Run("Other secret", func(t *testing.T) {
+		_, ok := Config{Secret: []byte("other secret")}.verify(value)
+		assert.False(t, ok)
+	})
+
+	t.Run("Unsigned", func(t *testing.T) {
+		_, ok := config.verify("session-id")
+		assert.False(t, ok)
+	})
+}
+
+func TestNewWithConfigPanicsWithoutSecret(t *testing.T) {
+	repository, err := session.NewRedisSessionRepository(nil, time.Minute)
+	assert.NoError(t, err)
+
+	assert.Panics(t, func() {
+		NewWithConfig(Config{Repository: repository})
+	})
+}
+
+func TestNewPanicsWithoutRepository(t *testing.T) {
+	assert.Panics(t, func() {
+		New(nil, time.Minute, testSecret)
+	})
+}
+
+func TestCookieIsSecureByDefault(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		secure bool
+	}{
+		{name: "Default", config: Config{CookieName: "session"}, secure: true},
+		{name: "Insecure", config: Config{CookieName: "session", Insecure: true}, secure: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+			tt.config.setCookie(c, "value", 60)
+			require.NoError(t, c.NoContent(http.StatusNoContent))
+
+			cookies := rec.Result().Cookies()
+			require.Len(t, cookies, 1)
+			assert.Equal(t, tt.secure, cookies[0].Secure)
+		})
+	}
+}
+
+// sessionClient : Keeps the session cookie between requests like a browser
+type sessionClient struct {
+	e      *echo.Echo
+	cookie *http.Cookie
+}
+
+func (s *sessionClient) do(t *testing.T, method string, path string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(method, path, nil)
+	if s.cookie != nil {
+		req.AddCookie(s.cookie)
+	}
+	rec := httptest.NewRecorder()
+	s.e.ServeHTTP(rec, req)
+
+	for _, cookie := range rec.Result().Cookies() {
+		if cookie.Name != DefaultConfig.CookieName {
+			continue
+		}
+		if cookie.MaxAge < 0 {
+			s.cookie = nil
+		} else {
+			s.cookie = cookie
+		}
+	}
+
+	return rec
+}
+
+func TestSessionMiddlewareTestContainer(t *testing.T) {
+	ctx := context.Background()
+	client := newContainerClient(t)
+	repository, err := session.NewRedisSessionRepository(client, 10*time.Minute)
+	assert.NoError(t, err)
+
+	e := echo.New()
+	e.Use(New(repository, repository.TTL, testSecret))
+	e.POST("/login", func(c echo.Context) error {
+		Start(c, c.QueryParam("username"))
+		return c.NoContent(http.StatusNoContent)
+	})
+	e.POST("/promote", func(c echo.Context) error {
+		Renew(c)
+		return c.NoContent(http.StatusNoContent)
+	})
+	e.POST("/logout", func(c echo.Context) error {
+		Destroy(c)
+		return c.NoContent(http.StatusNoContent)
+	})
+	e.GET("/me", func(c echo.Context) error {
+		current := Get(c)
+		if current == nil {
+			return echo.ErrUnauthorized
+		}
+		return c.String(http.StatusOK, current.Username)
+	})
+
+	t.Run("Anonymous request", func(t *testing.T) {
+		browser := &sessionClient{e: e}
+
+		rec := browser.do(t, http.MethodGet, "/me")
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Nil(t, browser.cookie)
+	})
+
+	t.Run("Login sets a secure cookie", func(t *testing.T) {
+		browser := &sessionClient{e: e}
+
+		browser.do(t, http.MethodPost, "/login?username=alice")
+		require.NotNil(t, browser.cookie)
+		assert.True(t, browser.cookie.Secure)
+		assert.True(t, browser.cookie.HttpOnly)
+		assert.Equal(t, http.SameSiteLaxMode, browser.cookie.SameSite)
+
+		rec := browser.do(t, http.MethodGet, "/me")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "alice", rec.Body.String())
+	})
+
+	t.Run("Login rotates a fixed session ID", func(t *testing.T) {
+		browser := &sessionClient{e: e}
+		browser.do(t, http.MethodPost, "/login?username=attacker")
+		fixed := browser.cookie
+		fixedID, _ := Config{Secret: testSecret}.verify(fixed.Value)
+
+		browser.do(t, http.MethodPost, "/login?username=victim")
+		assert.NotEqual(t, fixed.Value, browser.cookie.Value)
+
+		_, err := repository.Get(ctx, fixedID)
+		assert.ErrorIs(t, err, redis.Nil)
+	})
+
+	t.Run("Renew rotates the session ID", func(t *testing.T) {
+		browser := &sessionClient{e: e}
+		browser.do(t, http.MethodPost, "/login?username=bob")
+		before := browser.cookie
+
+		browser.do(t, http.MethodPost, "/promote")
+		assert.NotEqual(t, before.Value, browser.cookie.Value)
+
+		rec := browser.do(t, http.MethodGet, "/me")
+		assert.Equal(t, "bob", rec.Body.String())
+
+		old := &sessionClient{e: e, cookie: before}
+		rec = old.do(t, http.MethodGet, "/me")
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("Logout deletes the session", func(t *testing.T) {
+		browser := &sessionClient{e: e}
+		browser.do(t, http.MethodPost, "/login?username=carol")
+		loggedIn := browser.cookie
+
+		browser.do(t, http.MethodPost, "/logout")
+		assert.Nil(t, browser.cookie)
+
+		replayed := &sessionClient{e: e, cookie: loggedIn}
+		rec := replayed.do(t, http.MethodGet, "/me")
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("Forged cookie is ignored", func(t *testing.T) {
+		browser := &sessionClient{e: e}
+		browser.do(t, http.MethodPost, "/login?username=dave")
+		sessionID, _ := Config{Secret: testSecret}.verify(browser.cookie.Value)
+
+		forged := &sessionClient{e: e, cookie: &http.Cookie{Name: DefaultConfig.CookieName, Value: sessionID + ".forged"}}
+		rec := forged.do(t, http.MethodGet, "/me")
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+}
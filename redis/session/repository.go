@@ -0,0 +1,21 @@
+package session
+
+import (
+	sessions "github.com/christapa/testContainers/session"
+	redis "github.com/redis/go-redis/v9"
+)
+
+var _ sessions.SessionRepository = (*RedisSessionRepository)(nil)
+
+// errNotFound : Returned for sessions.ErrNotFound, it also matches redis.Nil, which RedisSessionRepository returned before
+var errNotFound error = notFoundError{}
+
+type notFoundError struct{}
+
+func (notFoundError) Error() string {
+	return sessions.ErrNotFound.Error()
+}
+
+func (notFoundError) Is(target error) bool {
+	return target == sessions.ErrNotFound || target == redis.Nil
+}
@@ -0,0 +1,133 @@
+package session
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	sessions "github.com/christapa/testContainers/session"
+	"github.com/google/uuid"
+	redis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	oldKey = sessions.Key{ID: 1, Secret: bytes.Repeat([]byte("o"), sessions.MinKeySize)}
+	newKey = sessions.Key{ID: 2, Secret: bytes.Repeat([]byte("n"), sessions.MinKeySize)}
+)
+
+func newTestKeyring(t *testing.T, active sessions.Key, previous ...sessions.Key) *sessions.Keyring {
+	t.Helper()
+
+	keyring, err := sessions.NewKeyring(active, previous...)
+	require.NoError(t, err)
+	return keyring
+}
+
+func TestRedisSessionRepository_Sealed(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("EncryptedAndHashed", func(t *testing.T) {
+		sessionRedisRepository := newTestRepository(t, 10*time.Minute)
+		sessionRedisRepository.Sealer = sessions.NewAESGCMSealer(newTestKeyring(t, oldKey))
+		session := newTestSession(t)
+
+		err := sessionRedisRepository.Save(ctx, session)
+		require.NoError(t, err)
+
+		savedSession, err := sessionRedisRepository.Get(ctx, session.SessionID)
+		require.NoError(t, err)
+		assert.Equal(t, session.CSRFToken, savedSession.CSRFToken)
+
+		// Neither the ID nor the values appear in Redis
+		exists, err := redisClient.Exists(ctx, session.SessionID).Result()
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), exists)
+
+		stored, err := redisClient.Get(ctx, sessionKey(session.SessionID)).Bytes()
+		require.NoError(t, err)
+		assert.False(t, bytes.Contains(stored, []byte(session.CSRFToken)))
+	})
+
+	t.Run("ForgedSession", func(t *testing.T) {
+		sessionRedisRepository := newTestRepository(t, 10*time.Minute)
+		sessionRedisRepository.Sealer = sessions.NewHMACSealer(newTestKeyring(t, oldKey))
+		sessionID := uuid.New().String()
+
+		err := redisClient.Set(ctx, sessionKey(sessionID), `{"SessionID":"`+sessionID+`","Username":"admin"}`, time.Minute).Err()
+		require.NoError(t, err)
+
+		_, err = sessionRedisRepository.Get(ctx, sessionID)
+		assert.ErrorIs(t, err, sessions.ErrInvalidSeal)
+
+		err = sessionRedisRepository.Delete(ctx, sessionID)
+		assert.NoError(t, err)
+
+		_, err = sessionRedisRepository.Get(ctx, sessionID)
+		assert.ErrorIs(t, err, redis.Nil)
+	})
+
+	t.Run("KeyRotation", func(t *testing.T) {
+		oldRepository := newTestRepository(t, 10*time.Minute)
+		oldRepository.Sealer = sessions.NewAESGCMSealer(newTestKeyring(t, oldKey))
+		session := newTestSession(t)
+		require.NoError(t, oldRepository.Save(ctx, session))
+
+		rotatedRepository := newTestRepository(t, 10*time.Minute)
+		rotatedRepository.Sealer = sessions.NewAESGCMSealer(newTestKeyring(t, newKey, oldKey))
+
+		savedSession, err := rotatedRepository.Get(ctx, session.SessionID)
+		require.NoError(t, err)
+		require.NoError(t, rotatedRepository.Save(ctx, savedSession))
+
+		// Saved again with the new key, the old one can be dropped
+		newRepository := newTestRepository(t, 10*time.Minute)
+		newRepository.Sealer = sessions.NewAESGCMSealer(newTestKeyring(t, newKey))
+		savedSession, err = newRepository.Get(ctx, session.SessionID)
+		assert.NoError(t, err)
+		assert.Equal(t, session.Username, savedSession.Username)
+	})
+
+	t.Run("RawKeyIsNotRead", func(t *testing.T) {
+		sessionRedisRepository := newTestRepository(t, 10*time.Minute)
+		sessionID := uuid.New().String()
+
+		err := redisClient.Set(ctx, sessionID, `{"SessionID":"`+sessionID+`","Username":"test"}`, time.Minute).Err()
+		require.NoError(t, err)
+
+		_, err = sessionRedisRepository.Get(ctx, sessionID)
+		assert.ErrorIs(t, err, sessions.ErrNotFound)
+	})
+
+	t.Run("LeakedKeyIsNotAnID", func(t *testing.T) {
+		sessionRedisRepository := newTestRepository(t, 10*time.Minute)
+		sessionRedisRepository.MigrateRawIDs = true
+		session := &sessions.Session{SessionID: uuid.New().String(), Username: "test"}
+		require.NoError(t, sessionRedisRepository.Save(ctx, session))
+
+		_, err := sessionRedisRepository.Get(ctx, sessionKey(session.SessionID))
+		assert.ErrorIs(t, err, sessions.ErrNotFound)
+	})
+
+	t.Run("RawKeyIsMoved", func(t *testing.T) {
+		sessionRedisRepository := newTestRepository(t, 10*time.Minute)
+		sessionRedisRepository.MigrateRawIDs = true
+		sessionID := uuid.New().String()
+
+		err := redisClient.Set(ctx, sessionID, `{"SessionID":"`+sessionID+`","Username":"test"}`, time.Minute).Err()
+		require.NoError(t, err)
+
+		_, err = sessionRedisRepository.Get(ctx, sessionID)
+		require.NoError(t, err)
+
+		exists, err := redisClient.Exists(ctx, sessionID).Result()
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), exists)
+
+		exists, err = redisClient.Exists(ctx, sessionKey(sessionID)).Result()
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), exists)
+	})
+}
@@ -2,55 +2,208 @@ package session
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
+	sessions "github.com/christapa/testContainers/session"
 	redis "github.com/redis/go-redis/v9"
 )
 
-// Implement the encoding.BinaryUnmarshaler interface
-type Session struct {
-	SessionID string
-	Username  string
+type RedisSessionRepository struct {
+	client *redis.Client
+	TTL    time.Duration
+	// Codec of the saved sessions, sessions.JSONCodec by default
+	Codec sessions.Codec
+	// Sealer signs or encrypts the saved sessions, nil to store them as they are
+	// Once set, sessions that are not sealed are not read
+	Sealer sessions.Sealer
+	// MigrateRawIDs moves the sessions saved under their raw ID, before the IDs were hashed, to their hashed key
+	// Only enable it until the old sessions have expired, it is ignored with a Sealer
+	MigrateRawIDs bool
 }
 
-func (s *Session) MarshalBinary() ([]byte, error) {
-	return json.Marshal(s)
-}
+// NewRedisSessionRepository : ttl must be at least 1ms, the sessions expire in milliseconds and Redis rejects PX 0
+func NewRedisSessionRepository(client *redis.Client, ttl time.Duration) (*RedisSessionRepository, error) {
+	if ttl < time.Millisecond {
+		return nil, fmt.Errorf("session TTL %s is below 1ms", ttl)
+	}
 
-func (s *Session) UnmarshalBinary(data []byte) error {
-	return json.Unmarshal(data, s)
+	return &RedisSessionRepository{
+		client: client,
+		TTL:    ttl,
+		Codec:  sessions.JSONCodec,
+	}, nil
 }
 
-func (s *Session) getKey() string {
-	return s.SessionID
+// Save : The session is also added to the index of its user
+// The session is saved with sessions.CurrentVersion, the given one is left as it is
+func (r *RedisSessionRepository) Save(ctx context.Context, session *sessions.Session) error {
+	key := sessionKey(session.SessionID)
+
+	value, err := r.encode(key, session)
+	if err != nil {
+		return err
+	}
+
+	return saveScript.Run(ctx, r.client,
+		[]string{key, userIndexKey(session.Username)},
+		value, r.TTL.Milliseconds(), key,
+	).Err()
 }
 
-func (s *Session) getValue() *Session {
-	return s
+// Get : See MigrateRawIDs for the sessions saved before the IDs were hashed
+func (r *RedisSessionRepository) Get(ctx context.Context, sessionID string) (*sessions.Session, error) {
+	key := sessionKey(sessionID)
+
+	data, err := r.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		if r.MigrateRawIDs && r.Sealer == nil {
+			return r.migrate(ctx, sessionID)
+		}
+		return nil, errNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return r.decode(key, data)
 }
 
-type RedisSessionRepository struct {
-	client *redis.Client
-	TTL    time.Duration
+// migrate : Move the session saved under its raw ID to its hashed key
+// The keys of the repository are never read as an ID, a leaked key does not give access to its session
+func (r *RedisSessionRepository) migrate(ctx context.Context, sessionID string) (*sessions.Session, error) {
+	if strings.HasPrefix(sessionID, "session:") {
+		return nil, errNotFound
+	}
+
+	legacy, err := r.client.Get(ctx, sessionID).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, errNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := r.decode(sessionID, legacy)
+	if err != nil {
+		return nil, err
+	}
+	// Any other value stored at this key is not a session
+	if session.SessionID != sessionID {
+		return nil, errNotFound
+	}
+	if err := r.Save(ctx, session); err != nil {
+		return nil, err
+	}
+	if err := r.client.Del(ctx, sessionID).Err(); err != nil {
+		return nil, err
+	}
+	return session, nil
 }
 
-func NewRedisSessionRepository(client *redis.Client, ttl time.Duration) *RedisSessionRepository {
-	return &RedisSessionRepository{
-		client: client,
-		TTL:    ttl,
+// Delete : Deleting a session that does not exist is not an error
+func (r *RedisSessionRepository) Delete(ctx context.Context, sessionID string) error {
+	session, err := r.Get(ctx, sessionID)
+	if errors.Is(err, sessions.ErrNotFound) {
+		return nil
 	}
+	// The user is unknown, the index is cleaned when it is listed
+	if errors.Is(err, sessions.ErrInvalidSeal) {
+		return r.client.Del(ctx, sessionKey(sessionID)).Err()
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, sessionKey(session.SessionID))
+		pipe.ZRem(ctx, userIndexKey(session.Username), sessionKey(session.SessionID))
+		return nil
+	})
+	return err
 }
 
-func (r *RedisSessionRepository) Save(ctx context.Context, session *Session) error {
-	return r.client.Set(ctx, session.getKey(), session.getValue(), r.TTL).Err()
+// Touch : Extend the session by TTL from now
+func (r *RedisSessionRepository) Touch(ctx context.Context, sessionID string) error {
+	session, err := r.Get(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+
+	touched, err := touchScript.Run(ctx, r.client,
+		[]string{sessionKey(session.SessionID), userIndexKey(session.Username)},
+		r.TTL.Milliseconds(), sessionKey(session.SessionID),
+	).Int()
+	if err != nil {
+		return err
+	}
+	if touched == 0 {
+		return errNotFound
+	}
+	return nil
 }
 
-func (r *RedisSessionRepository) Get(ctx context.Context, sessionID string) (*Session, error) {
-	session := &Session{}
-	err := r.client.Get(ctx, sessionID).Scan(session)
+// ListByUsername : Sessions of the user that have not expired, in no particular order
+func (r *RedisSessionRepository) ListByUsername(ctx context.Context, username string) ([]*sessions.Session, error) {
+	index := userIndexKey(username)
+
+	keys, err := listScript.Run(ctx, r.client, []string{index}).StringSlice()
 	if err != nil {
 		return nil, err
 	}
-	return session, nil
+	if len(keys) == 0 {
+		return []*sessions.Session{}, nil
+	}
+
+	values, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	listed := make([]*sessions.Session, 0, len(values))
+	stale := make([]any, 0)
+	for i, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			// Deleted without going through the repository
+			stale = append(stale, keys[i])
+			continue
+		}
+
+		session, err := r.decode(keys[i], []byte(data))
+		if errors.Is(err, sessions.ErrInvalidSeal) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if session.Username != username {
+			stale = append(stale, keys[i])
+			continue
+		}
+		listed = append(listed, session)
+	}
+
+	if len(stale) > 0 {
+		if err := r.client.ZRem(ctx, index, stale...).Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	return listed, nil
+}
+
+// RevokeAllForUser : Delete every session of the user, returns the number of sessions deleted
+func (r *RedisSessionRepository) RevokeAllForUser(ctx context.Context, username string) (int64, error) {
+	return revokeScript.Run(ctx, r.client, []string{userIndexKey(username)}).Int64()
+}
+
+func (r *RedisSessionRepository) encode(key string, session *sessions.Session) ([]byte, error) {
+	return sessions.Encode(r.Codec, r.Sealer, key, session)
+}
+
+func (r *RedisSessionRepository) decode(key string, data []byte) (*sessions.Session, error) {
+	return sessions.Decode(r.Codec, r.Sealer, key, data)
 }
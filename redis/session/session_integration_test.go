@@ -11,6 +11,7 @@ import (
 	"testing"
 	"time"
 
+	sessions "github.com/christapa/testContainers/session"
 	"github.com/google/uuid"
 
 	redis "github.com/redis/go-redis/v9"
@@ -108,9 +109,9 @@ func TestRedisSessionRepository_Scenario(t *testing.T) {
 	defer freezeContainer()
 
 	t.Run("ReadSessionBeforeTTL", func(t *testing.T) {
-		sessionRedisRepository := NewRedisSessionRepository(redisClient, 10*time.Minute)
+		sessionRedisRepository := newTestRepository(t, 10*time.Minute)
 
-		session := &Session{
+		session := &sessions.Session{
 			SessionID: uuid.New().String(),
 			Username:  "test",
 		}
@@ -125,13 +126,16 @@ func TestRedisSessionRepository_Scenario(t *testing.T) {
 			t.Fatalf("Failed to get session: %v", err)
 		}
 
+		// Only the saved session is upgraded
+		assert.Zero(t, session.Version)
+		session.Version = sessions.CurrentVersion
 		assert.Equal(t, session, savedSession)
 	})
 
 	t.Run("ReadSessionAfterTTL", func(t *testing.T) {
-		sessionRedisRepository := NewRedisSessionRepository(redisClient, time.Millisecond*100)
+		sessionRedisRepository := newTestRepository(t, time.Millisecond*100)
 
-		session := &Session{
+		session := &sessions.Session{
 			SessionID: uuid.New().String(),
 			Username:  "test",
 		}
@@ -149,3 +153,152 @@ func TestRedisSessionRepository_Scenario(t *testing.T) {
 	})
 
 }
+
+// newTestRepository : Repository of the container started by TestMain
+func newTestRepository(t *testing.T, ttl time.Duration) *RedisSessionRepository {
+	t.Helper()
+
+	repository, err := NewRedisSessionRepository(redisClient, ttl)
+	if err != nil {
+		t.Fatalf("Failed to create the session repository: %v", err)
+	}
+	return repository
+}
+
+func TestNewRedisSessionRepositoryInvalidTTL(t *testing.T) {
+	for _, ttl := range []time.Duration{0, -time.Minute, time.Millisecond / 2} {
+		_, err := NewRedisSessionRepository(redisClient, ttl)
+		assert.Error(t, err, ttl)
+	}
+}
+
+func saveSessions(t *testing.T, repository *RedisSessionRepository, username string, count int) []*sessions.Session {
+	t.Helper()
+
+	saved := make([]*sessions.Session, 0, count)
+	for i := 0; i < count; i++ {
+		session := &sessions.Session{
+			SessionID: uuid.New().String(),
+			Username:  username,
+		}
+		if err := repository.Save(context.Background(), session); err != nil {
+			t.Fatalf("Failed to save session: %v", err)
+		}
+		saved = append(saved, session)
+	}
+
+	return saved
+}
+
+func TestRedisSessionRepository_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Delete", func(t *testing.T) {
+		sessionRedisRepository := newTestRepository(t, 10*time.Minute)
+		username := uuid.New().String()
+		saved := saveSessions(t, sessionRedisRepository, username, 2)
+
+		err := sessionRedisRepository.Delete(ctx, saved[0].SessionID)
+		assert.NoError(t, err)
+
+		_, err = sessionRedisRepository.Get(ctx, saved[0].SessionID)
+		assert.ErrorIs(t, err, redis.Nil)
+
+		listed, err := sessionRedisRepository.ListByUsername(ctx, username)
+		assert.NoError(t, err)
+		assert.Equal(t, []*sessions.Session{saved[1]}, listed)
+
+		// Already deleted
+		err = sessionRedisRepository.Delete(ctx, saved[0].SessionID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("TouchExtendsTTL", func(t *testing.T) {
+		sessionRedisRepository := newTestRepository(t, time.Millisecond*300)
+		saved := saveSessions(t, sessionRedisRepository, uuid.New().String(), 1)
+
+		for i := 0; i < 3; i++ {
+			time.Sleep(time.Millisecond * 200)
+			err := sessionRedisRepository.Touch(ctx, saved[0].SessionID)
+			assert.NoError(t, err)
+		}
+
+		savedSession, err := sessionRedisRepository.Get(ctx, saved[0].SessionID)
+		assert.NoError(t, err)
+		assert.Equal(t, saved[0], savedSession)
+
+		time.Sleep(time.Millisecond * 400)
+
+		err = sessionRedisRepository.Touch(ctx, saved[0].SessionID)
+		assert.ErrorIs(t, err, redis.Nil)
+	})
+
+	t.Run("ListByUsername", func(t *testing.T) {
+		sessionRedisRepository := newTestRepository(t, 10*time.Minute)
+		username := uuid.New().String()
+		saved := saveSessions(t, sessionRedisRepository, username, 3)
+		saveSessions(t, sessionRedisRepository, uuid.New().String(), 1)
+
+		listed, err := sessionRedisRepository.ListByUsername(ctx, username)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, saved, listed)
+
+		listed, err = sessionRedisRepository.ListByUsername(ctx, uuid.New().String())
+		assert.NoError(t, err)
+		assert.Empty(t, listed)
+	})
+
+	t.Run("ListByUsernameSkipsExpiredSessions", func(t *testing.T) {
+		shortRepository := newTestRepository(t, time.Millisecond*100)
+		longRepository := newTestRepository(t, 10*time.Minute)
+		username := uuid.New().String()
+		saveSessions(t, shortRepository, username, 2)
+		saved := saveSessions(t, longRepository, username, 1)
+
+		time.Sleep(time.Millisecond * 200)
+
+		listed, err := longRepository.ListByUsername(ctx, username)
+		assert.NoError(t, err)
+		assert.Equal(t, saved, listed)
+
+		count, err := redisClient.ZCard(ctx, userIndexKey(username)).Result()
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("IndexExpiresWithTheSessions", func(t *testing.T) {
+		sessionRedisRepository := newTestRepository(t, time.Millisecond*100)
+		username := uuid.New().String()
+		saveSessions(t, sessionRedisRepository, username, 2)
+
+		time.Sleep(time.Millisecond * 200)
+
+		exists, err := redisClient.Exists(ctx, userIndexKey(username)).Result()
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), exists)
+	})
+
+	t.Run("RevokeAllForUser", func(t *testing.T) {
+		sessionRedisRepository := newTestRepository(t, 10*time.Minute)
+		username := uuid.New().String()
+		saved := saveSessions(t, sessionRedisRepository, username, 3)
+		otherSessions := saveSessions(t, sessionRedisRepository, uuid.New().String(), 1)
+
+		revoked, err := sessionRedisRepository.RevokeAllForUser(ctx, username)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), revoked)
+
+		for _, session := range saved {
+			_, err := sessionRedisRepository.Get(ctx, session.SessionID)
+			assert.ErrorIs(t, err, redis.Nil)
+		}
+
+		listed, err := sessionRedisRepository.ListByUsername(ctx, username)
+		assert.NoError(t, err)
+		assert.Empty(t, listed)
+
+		savedSession, err := sessionRedisRepository.Get(ctx, otherSessions[0].SessionID)
+		assert.NoError(t, err)
+		assert.Equal(t, otherSessions[0], savedSession)
+	})
+}
@@ -0,0 +1,54 @@
+package session
+
+import (
+	"encoding/json"
+	"slices"
+)
+
+// SetAttribute : The value is stored as JSON, whatever the codec of the repository
+func SetAttribute[T any](session *Session, name string, value T) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	if session.Attributes == nil {
+		session.Attributes = make(map[string][]byte)
+	}
+	session.Attributes[name] = data
+	return nil
+}
+
+// GetAttribute : The zero value and false when the session has no such attribute
+func GetAttribute[T any](session *Session, name string) (T, bool, error) {
+	var value T
+
+	data, found := session.Attributes[name]
+	if !found {
+		return value, false, nil
+	}
+
+	if err := json.Unmarshal(data, &value); err != nil {
+		return value, true, err
+	}
+	return value, true, nil
+}
+
+func (s *Session) DeleteAttribute(name string) {
+	delete(s.Attributes, name)
+}
+
+func (s *Session) HasRole(role string) bool {
+	return slices.Contains(s.Roles, role)
+}
+
+func (s *Session) AddFlash(message string) {
+	s.Flashes = append(s.Flashes, message)
+}
+
+// PopFlashes : The flashes are removed once read
+func (s *Session) PopFlashes() []string {
+	flashes := s.Flashes
+	s.Flashes = nil
+	return flashes
+}
@@ -0,0 +1,41 @@
+package session
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionAttributes(t *testing.T) {
+	type cart struct {
+		Items []string
+		Total int
+	}
+
+	session := &Session{}
+
+	_, found, err := GetAttribute[cart](session, "cart")
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	err = SetAttribute(session, "cart", cart{Items: []string{"book"}, Total: 12})
+	require.NoError(t, err)
+
+	value, found, err := GetAttribute[cart](session, "cart")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, cart{Items: []string{"book"}, Total: 12}, value)
+
+	_, _, err = GetAttribute[int](session, "cart")
+	assert.Error(t, err)
+
+	session.DeleteAttribute("cart")
+	_, found, _ = GetAttribute[cart](session, "cart")
+	assert.False(t, found)
+
+	session.AddFlash("first")
+	session.AddFlash("second")
+	assert.Equal(t, []string{"first", "second"}, session.PopFlashes())
+	assert.Empty(t, session.PopFlashes())
+}
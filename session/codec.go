@@ -0,0 +1,117 @@
+package session
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Codec : Encoding of the stored sessions
+// The ID is written before the payload, so sessions saved with another codec can still be read
+// JSON sessions are written without their ID, as before the codecs, so older binaries still read them
+type Codec interface {
+	ID() byte
+	Marshal(session *Session) ([]byte, error)
+	Unmarshal(data []byte, session *Session) error
+}
+
+const (
+	jsonCodecID    byte = 'j'
+	gobCodecID     byte = 'g'
+	msgpackCodecID byte = 'm'
+)
+
+var (
+	JSONCodec    Codec = jsonCodec{}
+	GobCodec     Codec = gobCodec{}
+	MsgpackCodec Codec = msgpackCodec{}
+)
+
+type jsonCodec struct{}
+
+func (jsonCodec) ID() byte { return jsonCodecID }
+
+func (jsonCodec) Marshal(session *Session) ([]byte, error) {
+	return json.Marshal(session)
+}
+
+func (jsonCodec) Unmarshal(data []byte, session *Session) error {
+	return json.Unmarshal(data, session)
+}
+
+type gobCodec struct{}
+
+func (gobCodec) ID() byte { return gobCodecID }
+
+func (gobCodec) Marshal(session *Session) ([]byte, error) {
+	var buffer bytes.Buffer
+	if err := gob.NewEncoder(&buffer).Encode(session); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func (gobCodec) Unmarshal(data []byte, session *Session) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(session)
+}
+
+type msgpackCodec struct{}
+
+func (msgpackCodec) ID() byte { return msgpackCodecID }
+
+func (msgpackCodec) Marshal(session *Session) ([]byte, error) {
+	return msgpack.Marshal(session)
+}
+
+func (msgpackCodec) Unmarshal(data []byte, session *Session) error {
+	return msgpack.Unmarshal(data, session)
+}
+
+// encode : The codec ID followed by a copy of the session with the current version
+func encode(codec Codec, session *Session) ([]byte, error) {
+	versioned := *session
+	versioned.Version = CurrentVersion
+
+	payload, err := codec.Marshal(&versioned)
+	if err != nil {
+		return nil, err
+	}
+
+	if codec.ID() == jsonCodecID {
+		return payload, nil
+	}
+
+	return append([]byte{codec.ID()}, payload...), nil
+}
+
+// decode : Read a session written by any known codec and upgrade it to the current version
+// JSON sessions are plain JSON, or prefixed by their ID when they were saved by an earlier version of encode
+func decode(codec Codec, data []byte, session *Session) error {
+	if len(data) == 0 {
+		return fmt.Errorf("empty session")
+	}
+
+	var err error
+	switch {
+	case data[0] == '{':
+		err = JSONCodec.Unmarshal(data, session)
+	case data[0] == codec.ID():
+		err = codec.Unmarshal(data[1:], session)
+	case data[0] == jsonCodecID:
+		err = JSONCodec.Unmarshal(data[1:], session)
+	case data[0] == gobCodecID:
+		err = GobCodec.Unmarshal(data[1:], session)
+	case data[0] == msgpackCodecID:
+		err = MsgpackCodec.Unmarshal(data[1:], session)
+	default:
+		return fmt.Errorf("unknown session codec %q", data[0])
+	}
+	if err != nil {
+		return err
+	}
+
+	return upgrade(session)
+}
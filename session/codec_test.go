@@ -0,0 +1,56 @@
+package session
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeVersions(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		version int
+		wantErr bool
+	}{
+		{name: "Before the version field", data: `{"Username":"test"}`, version: CurrentVersion},
+		{name: "Current version", data: `{"Version":1,"Username":"test"}`, version: CurrentVersion},
+		{name: "Newer version", data: `{"Version":99,"Username":"test"}`, version: 99},
+		{name: "Negative version", data: `{"Version":-1,"Username":"test"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var session Session
+			err := decode(JSONCodec, []byte(tt.data), &session)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.version, session.Version)
+		})
+	}
+}
+
+func TestEncodeJSONIsPlainJSON(t *testing.T) {
+	session := &Session{Username: "test"}
+
+	data, err := encode(JSONCodec, session)
+	require.NoError(t, err)
+	assert.Zero(t, session.Version, "The encoded session should not be changed")
+
+	// Readable by the binaries before the codecs
+	var decoded Session
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, CurrentVersion, decoded.Version)
+	assert.Equal(t, "test", decoded.Username)
+
+	// Sessions written with the JSON codec ID are still read
+	decoded = Session{}
+	require.NoError(t, decode(JSONCodec, append([]byte{jsonCodecID}, data...), &decoded))
+	assert.Equal(t, "test", decoded.Username)
+}
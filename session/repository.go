@@ -0,0 +1,58 @@
+package session
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+)
+
+// ErrNotFound : The session does not exist or has expired
+var ErrNotFound = errors.New("session not found")
+
+// SessionRepository : Storage of the sessions, every session expires TTL after it was saved or touched
+type SessionRepository interface {
+	Save(ctx context.Context, session *Session) error
+	// Get returns ErrNotFound when the session does not exist, and ErrInvalidSeal when it was not sealed by the keyring
+	Get(ctx context.Context, sessionID string) (*Session, error)
+	// Delete of a session that does not exist is not an error
+	Delete(ctx context.Context, sessionID string) error
+	// Touch returns ErrNotFound when the session has expired
+	Touch(ctx context.Context, sessionID string) error
+	ListByUsername(ctx context.Context, username string) ([]*Session, error)
+	// RevokeAllForUser returns the number of sessions deleted
+	RevokeAllForUser(ctx context.Context, username string) (int64, error)
+}
+
+// HashID : Sessions are stored under a hash of their ID, the stored keys can not be used as cookies
+func HashID(sessionID string) string {
+	hash := sha256.Sum256([]byte(sessionID))
+	return hex.EncodeToString(hash[:])
+}
+
+// Encode : The session encoded by the codec, then sealed for storageKey when sealer is not nil
+// The session is encoded with CurrentVersion, the given one is left as it is
+func Encode(codec Codec, sealer Sealer, storageKey string, session *Session) ([]byte, error) {
+	value, err := encode(codec, session)
+	if err != nil || sealer == nil {
+		return value, err
+	}
+
+	return sealer.Seal(storageKey, value)
+}
+
+// Decode : Read a session saved by Encode with any known codec, and upgrade it to CurrentVersion
+func Decode(codec Codec, sealer Sealer, storageKey string, data []byte) (*Session, error) {
+	if sealer != nil {
+		var err error
+		if data, err = sealer.Open(storageKey, data); err != nil {
+			return nil, err
+		}
+	}
+
+	session := &Session{}
+	if err := decode(codec, data, session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
@@ -0,0 +1,193 @@
+package session
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+)
+
+// MinKeySize : Secrets of the keyring are at least 32 bytes
+const MinKeySize = 32
+
+const (
+	hmacSealerID   byte = 'h'
+	aesGCMSealerID byte = 'a'
+)
+
+var ErrInvalidSeal = errors.New("session seal is invalid")
+
+// Key : ID is written with every sealed session, so the key that sealed it can be found after a rotation
+type Key struct {
+	ID     byte
+	Secret []byte
+}
+
+// Keyring : Sessions are sealed with the active key, and opened with the active or a previous one
+// To rotate, make the active key previous and add a new active key, sessions move to it when they are saved
+type Keyring struct {
+	active   Key
+	previous map[byte]Key
+}
+
+func NewKeyring(active Key, previous ...Key) (*Keyring, error) {
+	keyring := &Keyring{
+		active:   active,
+		previous: make(map[byte]Key, len(previous)),
+	}
+
+	seen := make(map[byte]bool, len(previous)+1)
+	for _, key := range append([]Key{active}, previous...) {
+		if len(key.Secret) < MinKeySize {
+			return nil, fmt.Errorf("session key %d is shorter than %d bytes", key.ID, MinKeySize)
+		}
+		if seen[key.ID] {
+			return nil, fmt.Errorf("session key %d is used twice", key.ID)
+		}
+		seen[key.ID] = true
+	}
+
+	for _, key := range previous {
+		keyring.previous[key.ID] = key
+	}
+
+	return keyring, nil
+}
+
+func (k *Keyring) find(id byte) (Key, bool) {
+	if id == k.active.ID {
+		return k.active, true
+	}
+
+	key, found := k.previous[id]
+	return key, found
+}
+
+// derive : Separate keys for each use of a secret
+func derive(secret []byte, label string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(label))
+	return mac.Sum(nil)
+}
+
+// Sealer : Protection of the encoded sessions in their storage
+// The storage key is bound to the seal, so a sealed value can not be moved to another session
+type Sealer interface {
+	Seal(storageKey string, payload []byte) ([]byte, error)
+	Open(storageKey string, data []byte) ([]byte, error)
+}
+
+// HMACSealer : The payload stays readable, any change to it is detected
+// Format : 'h', key ID, HMAC-SHA256 of the storage key and the payload, payload
+type HMACSealer struct {
+	keyring *Keyring
+}
+
+func NewHMACSealer(keyring *Keyring) *HMACSealer {
+	return &HMACSealer{keyring: keyring}
+}
+
+func (s *HMACSealer) Seal(storageKey string, payload []byte) ([]byte, error) {
+	key := s.keyring.active
+
+	sealed := make([]byte, 0, 2+sha256.Size+len(payload))
+	sealed = append(sealed, hmacSealerID, key.ID)
+	sealed = append(sealed, s.mac(key, storageKey, payload)...)
+	return append(sealed, payload...), nil
+}
+
+func (s *HMACSealer) Open(storageKey string, data []byte) ([]byte, error) {
+	if len(data) < 2+sha256.Size || data[0] != hmacSealerID {
+		return nil, ErrInvalidSeal
+	}
+
+	key, found := s.keyring.find(data[1])
+	if !found {
+		return nil, ErrInvalidSeal
+	}
+
+	signature := data[2 : 2+sha256.Size]
+	payload := data[2+sha256.Size:]
+	if !hmac.Equal(signature, s.mac(key, storageKey, payload)) {
+		return nil, ErrInvalidSeal
+	}
+
+	return payload, nil
+}
+
+func (s *HMACSealer) mac(key Key, storageKey string, payload []byte) []byte {
+	mac := hmac.New(sha256.New, derive(key.Secret, "session signature"))
+	mac.Write([]byte(storageKey))
+	mac.Write([]byte{0})
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// AESGCMSealer : The payload is encrypted and authenticated, the storage key is the additional data
+// Format : 'a', key ID, nonce, ciphertext
+type AESGCMSealer struct {
+	keyring *Keyring
+}
+
+func NewAESGCMSealer(keyring *Keyring) *AESGCMSealer {
+	return &AESGCMSealer{keyring: keyring}
+}
+
+func (s *AESGCMSealer) Seal(storageKey string, payload []byte) ([]byte, error) {
+	key := s.keyring.active
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	sealed := make([]byte, 0, 2+len(nonce)+len(payload)+aead.Overhead())
+	sealed = append(sealed, aesGCMSealerID, key.ID)
+	sealed = append(sealed, nonce...)
+	return aead.Seal(sealed, nonce, payload, []byte(storageKey)), nil
+}
+
+func (s *AESGCMSealer) Open(storageKey string, data []byte) ([]byte, error) {
+	if len(data) < 2 || data[0] != aesGCMSealerID {
+		return nil, ErrInvalidSeal
+	}
+
+	key, found := s.keyring.find(data[1])
+	if !found {
+		return nil, ErrInvalidSeal
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < 2+aead.NonceSize() {
+		return nil, ErrInvalidSeal
+	}
+
+	nonce := data[2 : 2+aead.NonceSize()]
+	payload, err := aead.Open(nil, nonce, data[2+aead.NonceSize():], []byte(storageKey))
+	if err != nil {
+		return nil, ErrInvalidSeal
+	}
+
+	return payload, nil
+}
+
+func newAEAD(key Key) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(derive(key.Secret, "session encryption"))
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
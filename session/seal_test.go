@@ -0,0 +1,85 @@
+package session
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	oldKey = Key{ID: 1, Secret: bytes.Repeat([]byte("o"), MinKeySize)}
+	newKey = Key{ID: 2, Secret: bytes.Repeat([]byte("n"), MinKeySize)}
+)
+
+func newTestKeyring(t *testing.T, active Key, previous ...Key) *Keyring {
+	t.Helper()
+
+	keyring, err := NewKeyring(active, previous...)
+	require.NoError(t, err)
+	return keyring
+}
+
+func TestNewKeyring(t *testing.T) {
+	_, err := NewKeyring(Key{ID: 1, Secret: []byte("short")})
+	assert.Error(t, err)
+
+	_, err = NewKeyring(oldKey, Key{ID: oldKey.ID, Secret: newKey.Secret})
+	assert.Error(t, err)
+
+	_, err = NewKeyring(newKey, oldKey)
+	assert.NoError(t, err)
+}
+
+func TestSealers(t *testing.T) {
+	payload := []byte(`{"Username":"test"}`)
+
+	sealers := map[string]func(keyring *Keyring) Sealer{
+		"HMAC":    func(keyring *Keyring) Sealer { return NewHMACSealer(keyring) },
+		"AES-GCM": func(keyring *Keyring) Sealer { return NewAESGCMSealer(keyring) },
+	}
+
+	for name, newSealer := range sealers {
+		t.Run(name, func(t *testing.T) {
+			sealer := newSealer(newTestKeyring(t, oldKey))
+
+			sealed, err := sealer.Seal("session:a", payload)
+			require.NoError(t, err)
+
+			opened, err := sealer.Open("session:a", sealed)
+			assert.NoError(t, err)
+			assert.Equal(t, payload, opened)
+
+			// Moved to another session
+			_, err = sealer.Open("session:b", sealed)
+			assert.ErrorIs(t, err, ErrInvalidSeal)
+
+			tampered := bytes.Clone(sealed)
+			tampered[len(tampered)-1] ^= 1
+			_, err = sealer.Open("session:a", tampered)
+			assert.ErrorIs(t, err, ErrInvalidSeal)
+
+			// Unsealed
+			_, err = sealer.Open("session:a", payload)
+			assert.ErrorIs(t, err, ErrInvalidSeal)
+
+			// The previous key still opens the sessions it sealed
+			rotated := newSealer(newTestKeyring(t, newKey, oldKey))
+			opened, err = rotated.Open("session:a", sealed)
+			assert.NoError(t, err)
+			assert.Equal(t, payload, opened)
+
+			// Once removed from the keyring, it does not
+			removed := newSealer(newTestKeyring(t, newKey))
+			_, err = removed.Open("session:a", sealed)
+			assert.ErrorIs(t, err, ErrInvalidSeal)
+		})
+	}
+
+	t.Run("AES-GCM encrypts", func(t *testing.T) {
+		sealed, err := NewAESGCMSealer(newTestKeyring(t, oldKey)).Seal("session:a", payload)
+		require.NoError(t, err)
+		assert.False(t, bytes.Contains(sealed, []byte("test")))
+	})
+}
@@ -0,0 +1,57 @@
+// Package session : Sessions, their codecs and sealers, shared by the repositories of every backend
+package session
+
+import (
+	"fmt"
+	"time"
+)
+
+// CurrentVersion : Version of the Session struct, older sessions are upgraded when they are read
+// 0 : SessionID and Username only
+// 1 : Roles, CSRF token, flashes, last activity and attributes
+const CurrentVersion = 1
+
+// upgrades : upgrades[v] turns a session of version v into version v+1
+var upgrades = []func(session *Session) error{
+	0: func(session *Session) error {
+		return nil
+	},
+}
+
+// Implement the encoding.BinaryMarshaler and encoding.BinaryUnmarshaler interfaces
+type Session struct {
+	Version      int
+	SessionID    string
+	Username     string
+	Roles        []string  `json:",omitempty" msgpack:",omitempty"`
+	CSRFToken    string    `json:",omitempty" msgpack:",omitempty"`
+	Flashes      []string  `json:",omitempty" msgpack:",omitempty"`
+	LastActivity time.Time `msgpack:",omitempty"`
+	// Attributes hold the other values as JSON, see SetAttribute and GetAttribute
+	Attributes map[string][]byte `json:",omitempty" msgpack:",omitempty"`
+}
+
+// MarshalBinary : Plain JSON, the session itself is not upgraded
+func (s *Session) MarshalBinary() ([]byte, error) {
+	return encode(JSONCodec, s)
+}
+
+func (s *Session) UnmarshalBinary(data []byte) error {
+	return decode(JSONCodec, data, s)
+}
+
+// upgrade : A session of a newer version is kept as it is, its unknown fields are lost when it is saved
+func upgrade(session *Session) error {
+	if session.Version < 0 {
+		return fmt.Errorf("invalid session version %d", session.Version)
+	}
+
+	for session.Version < CurrentVersion {
+		if err := upgrades[session.Version](session); err != nil {
+			return fmt.Errorf("failed to upgrade session from version %d: %w", session.Version, err)
+		}
+		session.Version++
+	}
+
+	return nil
+}
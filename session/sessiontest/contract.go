@@ -0,0 +1,164 @@
+// Package sessiontest : Contract that every session.SessionRepository must follow
+package sessiontest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/christapa/testContainers/session"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// NewRepository : Repository of the backend under test, whose sessions expire after ttl
+type NewRepository func(t *testing.T, ttl time.Duration) session.SessionRepository
+
+// Run : The usernames are random, so the backend can be shared by other tests
+func Run(t *testing.T, newRepository NewRepository) {
+	ctx := context.Background()
+
+	t.Run("SaveAndGet", func(t *testing.T) {
+		repository := newRepository(t, 10*time.Minute)
+		sessions := save(t, repository, uuid.New().String(), 1)
+
+		saved, err := repository.Get(ctx, sessions[0].SessionID)
+		require.NoError(t, err)
+		assert.Equal(t, sessions[0], saved)
+	})
+
+	t.Run("SaveOverwrites", func(t *testing.T) {
+		repository := newRepository(t, 10*time.Minute)
+		sessions := save(t, repository, uuid.New().String(), 1)
+
+		sessions[0].Roles = []string{"admin"}
+		require.NoError(t, repository.Save(ctx, sessions[0]))
+
+		saved, err := repository.Get(ctx, sessions[0].SessionID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"admin"}, saved.Roles)
+	})
+
+	t.Run("GetUnknownSession", func(t *testing.T) {
+		repository := newRepository(t, 10*time.Minute)
+
+		_, err := repository.Get(ctx, uuid.New().String())
+		assert.ErrorIs(t, err, session.ErrNotFound)
+	})
+
+	t.Run("SessionExpires", func(t *testing.T) {
+		repository := newRepository(t, 500*time.Millisecond)
+		sessions := save(t, repository, uuid.New().String(), 1)
+
+		time.Sleep(time.Second)
+
+		_, err := repository.Get(ctx, sessions[0].SessionID)
+		assert.ErrorIs(t, err, session.ErrNotFound)
+
+		err = repository.Touch(ctx, sessions[0].SessionID)
+		assert.ErrorIs(t, err, session.ErrNotFound)
+	})
+
+	t.Run("TouchExtendsTTL", func(t *testing.T) {
+		repository := newRepository(t, time.Second)
+		sessions := save(t, repository, uuid.New().String(), 1)
+
+		for i := 0; i < 3; i++ {
+			time.Sleep(600 * time.Millisecond)
+			require.NoError(t, repository.Touch(ctx, sessions[0].SessionID))
+		}
+
+		_, err := repository.Get(ctx, sessions[0].SessionID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		repository := newRepository(t, 10*time.Minute)
+		username := uuid.New().String()
+		sessions := save(t, repository, username, 2)
+
+		require.NoError(t, repository.Delete(ctx, sessions[0].SessionID))
+
+		_, err := repository.Get(ctx, sessions[0].SessionID)
+		assert.ErrorIs(t, err, session.ErrNotFound)
+
+		listed, err := repository.ListByUsername(ctx, username)
+		assert.NoError(t, err)
+		assert.Equal(t, []*session.Session{sessions[1]}, listed)
+
+		// Already deleted
+		assert.NoError(t, repository.Delete(ctx, sessions[0].SessionID))
+	})
+
+	t.Run("ListByUsername", func(t *testing.T) {
+		repository := newRepository(t, 10*time.Minute)
+		username := uuid.New().String()
+		sessions := save(t, repository, username, 3)
+		save(t, repository, uuid.New().String(), 1)
+
+		listed, err := repository.ListByUsername(ctx, username)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, sessions, listed)
+
+		listed, err = repository.ListByUsername(ctx, uuid.New().String())
+		assert.NoError(t, err)
+		assert.Empty(t, listed)
+	})
+
+	t.Run("ListByUsernameSkipsExpiredSessions", func(t *testing.T) {
+		shortRepository := newRepository(t, 500*time.Millisecond)
+		longRepository := newRepository(t, 10*time.Minute)
+		username := uuid.New().String()
+		save(t, shortRepository, username, 2)
+		sessions := save(t, longRepository, username, 1)
+
+		time.Sleep(time.Second)
+
+		listed, err := longRepository.ListByUsername(ctx, username)
+		assert.NoError(t, err)
+		assert.Equal(t, sessions, listed)
+	})
+
+	t.Run("RevokeAllForUser", func(t *testing.T) {
+		repository := newRepository(t, 10*time.Minute)
+		username := uuid.New().String()
+		sessions := save(t, repository, username, 3)
+		others := save(t, repository, uuid.New().String(), 1)
+
+		revoked, err := repository.RevokeAllForUser(ctx, username)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), revoked)
+
+		for _, revokedSession := range sessions {
+			_, err := repository.Get(ctx, revokedSession.SessionID)
+			assert.ErrorIs(t, err, session.ErrNotFound)
+		}
+
+		listed, err := repository.ListByUsername(ctx, username)
+		assert.NoError(t, err)
+		assert.Empty(t, listed)
+
+		saved, err := repository.Get(ctx, others[0].SessionID)
+		assert.NoError(t, err)
+		assert.Equal(t, others[0], saved)
+	})
+}
+
+func save(t *testing.T, repository session.SessionRepository, username string, count int) []*session.Session {
+	t.Helper()
+
+	sessions := make([]*session.Session, 0, count)
+	for i := 0; i < count; i++ {
+		// Sessions are read back with the current version
+		saved := &session.Session{
+			Version:   session.CurrentVersion,
+			SessionID: uuid.New().String(),
+			Username:  username,
+		}
+		require.NoError(t, repository.Save(context.Background(), saved))
+		sessions = append(sessions, saved)
+	}
+
+	return sessions
+}
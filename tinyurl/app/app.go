@@ -1,15 +1,30 @@
 package app
 
 import (
+	"context"
+	"errors"
+	"expvar"
 	"fmt"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	redis "github.com/redis/go-redis/v9"
+
+	user "github.com/christapa/testContainers/postgresql/user"
+	ratelimit "github.com/christapa/testContainers/redis/ratelimiter"
 
 	config "github.com/christapa/tinyurl/config"
 	tinyHttp "github.com/christapa/tinyurl/internal/tinyurl/api/http"
+	domain "github.com/christapa/tinyurl/internal/tinyurl/domain"
 	infra "github.com/christapa/tinyurl/internal/tinyurl/infra"
+	cache "github.com/christapa/tinyurl/internal/tinyurl/infra/cache"
 	services "github.com/christapa/tinyurl/internal/tinyurl/services"
 	logger "github.com/christapa/tinyurl/pkg/logger"
 	sql "github.com/christapa/tinyurl/pkg/sql"
@@ -17,6 +32,7 @@ import (
 
 func Run(config config.Config) {
 	e := echo.New()
+	e.IPExtractor = newIPExtractor(config.Server.TrustedProxies)
 
 	databaseConn, err := sql.NewConn(sql.DBConfig{
 		Host:     config.Database.Host,
@@ -30,19 +46,249 @@ func Run(config config.Config) {
 	}
 	defer databaseConn.Close()
 
-	repository := infra.NewUrlSqlRepository(databaseConn)
-	service := services.NewUrlService(repository)
-	handler := tinyHttp.NewHttpHandler(service)
+	validator := domain.NewURLValidator(newValidationRules(config.Validation))
+
+	generator, err := newShortenURLGenerator(config.Slug, databaseConn)
+	if err != nil {
+		logger.Fatalf("Failed to create shorten URL generator: %v", err)
+	}
+
+	expirationPolicy, err := domain.ParseExpirationPolicy(config.Creation.ExpirationPolicy)
+	if err != nil {
+		logger.Fatalf("Invalid CREATE_EXPIRATION_POLICY: %v", err)
+	}
+
+	// Redis holds the url cache and the rate limits, both are disabled without it
+	var redisClient *redis.Client
+	if config.Cache.Addr != "" {
+		redisClient = redis.NewClient(&redis.Options{
+			Addr:     config.Cache.Addr,
+			Password: config.Cache.Password,
+			DB:       config.Cache.DB,
+		})
+		defer redisClient.Close()
+
+		if err := redisClient.Ping(context.Background()).Err(); err != nil {
+			logger.Fatalf("Failed to connect to redis: %v", err)
+		}
+	}
+
+	var repository domain.UrlRepository = infra.NewUrlSqlRepository(databaseConn)
+	if redisClient != nil {
+		repository = cache.NewUrlRedisCache(repository, redisClient, config.Cache.TTL, config.Cache.NegativeTTL)
+	}
+
+	counters := services.NewCounterAggregator(
+		repository,
+		config.Counters.BatchSize,
+		config.Counters.MaxPending,
+		config.Counters.FlushInterval,
+	)
+	counters.Start()
+
+	service := services.NewUrlService(counters, generator, expirationPolicy, validator)
+
+	reaper := services.NewExpirationReaper(
+		repository,
+		config.Reaper.Interval,
+		config.Reaper.BatchSize,
+		config.Reaper.MaxBatches,
+		config.Reaper.Archive,
+	)
+	reaper.Start()
+
+	clickRepository := infra.NewClickSqlRepository(databaseConn)
+	clickRecorder := services.NewClickRecorder(
+		clickRepository,
+		config.Clicks.AnonymizeIP,
+		config.Clicks.BufferSize,
+		config.Clicks.BatchSize,
+		config.Clicks.FlushInterval,
+	)
+	clickRecorder.Start()
 
+	statsService := services.NewStatsService(service, clickRepository)
+
+	handler := tinyHttp.NewHttpHandler(service, clickRecorder, statsService)
+
+	userService := services.NewUserService(user.NewSqlUserRepository(databaseConn), config.Auth.MaxVerifications)
+
+	// The rate limits come first, a rejected request never reaches the password hashing
 	e.Use(middleware.Logger())
 	e.Use(middleware.Recover())
+	var authFailures tinyHttp.AuthFailures
+	if redisClient != nil {
+		rateLimitRules, err := newRateLimitRules(config.RateLimit, redisClient)
+		if err != nil {
+			logger.Fatalf("Invalid rate limit configuration: %v", err)
+		}
+		e.Use(tinyHttp.NewRateLimitMiddleware(rateLimitRules, config.RateLimit.APIKeyHeader, tinyHttp.NewAPIKeys(config.RateLimit.APIKeys)))
+
+		if config.RateLimit.AuthFailureLimit > 0 {
+			authFailures = cache.NewAuthFailureRedisCounter(redisClient, int64(config.RateLimit.AuthFailureLimit), config.RateLimit.AuthFailureWindow)
+		}
+	}
+	e.Use(tinyHttp.NewAuthMiddleware(userService, authFailures))
 
 	e.GET("/health", func(c echo.Context) error {
 		return c.JSON(http.StatusOK, struct{ Status string }{Status: "OK"})
 	})
 
+	expvar.Publish("reaper", expvar.Func(func() any { return reaper.Stats() }))
+	expvar.Publish("counters", expvar.Func(func() any {
+		return map[string]int64{"pending": counters.Pending(), "dropped": counters.Dropped()}
+	}))
+	expvar.Publish("clicks", expvar.Func(func() any {
+		return map[string]int64{"dropped": clickRecorder.Dropped()}
+	}))
+
 	tinyHttp.RegisterHandlers(e, handler)
 
 	address := fmt.Sprintf(":%d", config.Server.Port)
-	e.Logger.Fatal(e.Start(address))
+	go func() {
+		if err := e.Start(address); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Fatalf("Failed to start server: %v", err)
+		}
+	}()
+
+	// The metrics are not public, they are served on their own address
+	var adminServer *http.Server
+	if config.Server.AdminAddr != "" {
+		adminMux := http.NewServeMux()
+		adminMux.Handle("/debug/vars", expvar.Handler())
+		adminServer = &http.Server{Addr: config.Server.AdminAddr, Handler: adminMux, ReadHeaderTimeout: 5 * time.Second}
+
+		go func() {
+			if err := adminServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logger.Fatalf("Failed to start admin server: %v", err)
+			}
+		}()
+	}
+
+	// Graceful shutdown : stop serving, then store what is still buffered
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+	<-quit
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.Server.ShutdownTimeout)
+	defer cancel()
+
+	if err := e.Shutdown(ctx); err != nil {
+		logger.Errorf("Failed to shutdown server: %v", err)
+	}
+
+	if adminServer != nil {
+		if err := adminServer.Shutdown(ctx); err != nil {
+			logger.Errorf("Failed to shutdown admin server: %v", err)
+		}
+	}
+
+	if err := reaper.Close(ctx); err != nil {
+		logger.Errorf("Failed to stop the expiration reaper: %v", err)
+	}
+
+	if err := counters.Close(ctx); err != nil {
+		logger.Errorf("Failed to write %d pending counter increments: %v", counters.Pending(), err)
+	}
+
+	if err := clickRecorder.Close(ctx); err != nil {
+		logger.Errorf("Failed to store buffered click events: %v", err)
+	}
+
+	logger.Infof("Dropped %d counter increments and %d click events", counters.Dropped(), clickRecorder.Dropped())
+}
+
+// newRateLimitRules : The rules share one circuit breaker, Redis is down for all of them, and the override rules
+func newRateLimitRules(rateLimit config.RateLimit, client *redis.Client) ([]tinyHttp.RateLimitRule, error) {
+	failurePolicy, err := ratelimit.ParseFailurePolicy(rateLimit.FailurePolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	breaker := ratelimit.NewCircuitBreaker(rateLimit.BreakerThreshold, rateLimit.BreakerTimeout)
+	ruleStore := ratelimit.NewRuleStore(client, rateLimit.RulesHash, rateLimit.RulesCacheTTL)
+	newLimiter := func(limit int, window time.Duration) (ratelimit.Limiter, error) {
+		limiter, err := ratelimit.NewRateLimiter(client, window, int64(limit))
+		if err != nil {
+			return nil, err
+		}
+
+		return ratelimit.NewFailSafeLimiter(
+			ratelimit.NewOverrideLimiter(client, limiter, ruleStore),
+			failurePolicy,
+			breaker,
+			ratelimit.NewLocalLimiter(window, int64(limit), int64(rateLimit.Instances)),
+		), nil
+	}
+
+	rules := make([]tinyHttp.RateLimitRule, 0, 2)
+	if rateLimit.CreateLimit > 0 {
+		limiter, err := newLimiter(rateLimit.CreateLimit, rateLimit.CreateWindow)
+		if err != nil {
+			return nil, fmt.Errorf("create: %w", err)
+		}
+		rules = append(rules, tinyHttp.NewRateLimitRule(http.MethodPost, "/create", limiter))
+	}
+
+	if rateLimit.RedirectLimit > 0 {
+		limiter, err := newLimiter(rateLimit.RedirectLimit, rateLimit.RedirectWindow)
+		if err != nil {
+			return nil, fmt.Errorf("redirect: %w", err)
+		}
+		rules = append(rules, tinyHttp.NewRateLimitRule(http.MethodGet, "/:slug", limiter))
+	}
+
+	return rules, nil
+}
+
+// newIPExtractor : Without trusted proxies the client IP is the peer address, the headers are never read
+func newIPExtractor(trustedProxies config.List) echo.IPExtractor {
+	if len(trustedProxies) == 0 {
+		return echo.ExtractIPDirect()
+	}
+
+	options := []echo.TrustOption{echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false)}
+	for _, proxy := range trustedProxies {
+		_, network, err := net.ParseCIDR(proxy)
+		if err != nil {
+			logger.Fatalf("Invalid CIDR %q in SERVER_TRUSTED_PROXIES: %v", proxy, err)
+		}
+		options = append(options, echo.TrustIPRange(network))
+	}
+
+	return echo.ExtractIPFromXFFHeader(options...)
+}
+
+func newValidationRules(validation config.URLValidation) domain.ValidationRules {
+	ports := make([]int, 0, len(validation.AllowedPorts))
+	for _, rawPort := range validation.AllowedPorts {
+		port, err := strconv.Atoi(rawPort)
+		if err != nil {
+			logger.Fatalf("Invalid port %q in URL_ALLOWED_PORTS: %v", rawPort, err)
+		}
+		ports = append(ports, port)
+	}
+
+	return domain.ValidationRules{
+		AllowedSchemes:        validation.AllowedSchemes,
+		AllowedPorts:          ports,
+		MaxLength:             validation.MaxLength,
+		AllowPrivateAddresses: validation.AllowPrivateAddresses,
+		AllowUserInfo:         validation.AllowUserInfo,
+	}
+}
+
+func newShortenURLGenerator(slug config.Slug, querier sql.Querier) (domain.ShortenURLGenerator, error) {
+	switch slug.Strategy {
+	case "hash":
+		return domain.NewHashShortenURLGenerator(slug.Length)
+	case "random":
+		return domain.NewRandomShortenURLGenerator(slug.Alphabet, slug.Length)
+	case "sequence":
+		return domain.NewSequenceShortenURLGenerator(infra.NewSqlSequence(querier, infra.SlugSequenceName), slug.Length)
+	case "obfuscated":
+		return domain.NewObfuscatedShortenURLGenerator(infra.NewSqlSequence(querier, infra.SlugSequenceName), slug.Alphabet, slug.Length)
+	default:
+		return nil, fmt.Errorf("unknown slug strategy %q", slug.Strategy)
+	}
 }
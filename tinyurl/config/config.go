@@ -2,6 +2,8 @@ package config
 
 import (
 	"log"
+	"strings"
+	"time"
 
 	env "github.com/Netflix/go-env"
 )
@@ -11,10 +13,33 @@ type Config struct {
 
 	// Only Postgresql for now
 	Database PostgresqlDatabase `json:"database"`
+
+	Validation URLValidation `json:"validation"`
+
+	Slug Slug `json:"slug"`
+
+	Creation Creation `json:"creation"`
+
+	Clicks Clicks `json:"clicks"`
+
+	Cache Cache `json:"cache"`
+
+	Counters Counters `json:"counters"`
+
+	Reaper Reaper `json:"reaper"`
+
+	RateLimit RateLimit `json:"rateLimit"`
+
+	Auth Auth `json:"auth"`
 }
 
+// Server : AdminAddr serves /debug/vars apart from the public port, only on the loopback by default, empty disables it
+// TrustedProxies are the CIDRs of the proxies whose X-Forwarded-For is read, the peer address is the client IP without them
 type Server struct {
-	Port int `json:"port" env:"SERVER_PORT,default=8080"`
+	Port            int           `json:"port" env:"SERVER_PORT,default=8080"`
+	AdminAddr       string        `json:"adminAddr" env:"SERVER_ADMIN_ADDR,default=127.0.0.1:8081"`
+	TrustedProxies  List          `json:"trustedProxies" env:"SERVER_TRUSTED_PROXIES"`
+	ShutdownTimeout time.Duration `json:"shutdownTimeout" env:"SERVER_SHUTDOWN_TIMEOUT,default=10s"`
 }
 
 // Use Netflix go env
@@ -26,6 +51,112 @@ type PostgresqlDatabase struct {
 	Database string `json:"database" env:"DATABASE_NAME,default=tinyurl"`
 }
 
+// URLValidation : Rules applied to the original URL before shortening it
+type URLValidation struct {
+	AllowedSchemes        List `json:"allowedSchemes" env:"URL_ALLOWED_SCHEMES,default=http https"`
+	AllowedPorts          List `json:"allowedPorts" env:"URL_ALLOWED_PORTS"`
+	MaxLength             int  `json:"maxLength" env:"URL_MAX_LENGTH,default=2048"`
+	AllowPrivateAddresses bool `json:"allowPrivateAddresses" env:"URL_ALLOW_PRIVATE_ADDRESSES,default=false"`
+	AllowUserInfo         bool `json:"allowUserInfo" env:"URL_ALLOW_USER_INFO,default=false"`
+}
+
+// Slug : Strategy used to generate shorten URLs
+// Strategy is one of hash, random, sequence or obfuscated
+// Length is the exact length for hash and random, the minimum one for sequence and obfuscated
+// Alphabet is used by random and obfuscated, base62 when empty, only A-Z a-z 0-9 - _ ~ are allowed
+type Slug struct {
+	Strategy string `json:"strategy" env:"SLUG_STRATEGY,default=hash"`
+	Length   int    `json:"length" env:"SLUG_LENGTH,default=8"`
+	Alphabet string `json:"alphabet" env:"SLUG_ALPHABET"`
+}
+
+// Creation : Behaviour when an original URL is shortened again
+// ExpirationPolicy is one of reject, extend or new, used when the expiration dates differ
+type Creation struct {
+	ExpirationPolicy string `json:"expirationPolicy" env:"CREATE_EXPIRATION_POLICY,default=new"`
+}
+
+// Clicks : Click events are buffered in memory and stored in batches
+type Clicks struct {
+	AnonymizeIP   bool          `json:"anonymizeIp" env:"CLICKS_ANONYMIZE_IP,default=true"`
+	BufferSize    int           `json:"bufferSize" env:"CLICKS_BUFFER_SIZE,default=10000"`
+	BatchSize     int           `json:"batchSize" env:"CLICKS_BATCH_SIZE,default=500"`
+	FlushInterval time.Duration `json:"flushInterval" env:"CLICKS_FLUSH_INTERVAL,default=1s"`
+}
+
+// Counters : Redirect counters are summed in memory and written in batches
+// MaxPending is the number of distinct shorten URLs kept in memory, increments above are dropped
+type Counters struct {
+	BatchSize     int           `json:"batchSize" env:"COUNTERS_BATCH_SIZE,default=500"`
+	MaxPending    int           `json:"maxPending" env:"COUNTERS_MAX_PENDING,default=100000"`
+	FlushInterval time.Duration `json:"flushInterval" env:"COUNTERS_FLUSH_INTERVAL,default=1s"`
+}
+
+// Reaper : Expired urls are removed in the background, every Interval, 0 disables it
+// Each run removes at most MaxBatches batches of BatchSize urls with their clicks, Archive moves them to urls_archive and clicks_archive
+type Reaper struct {
+	Interval   time.Duration `json:"interval" env:"REAPER_INTERVAL,default=1m"`
+	BatchSize  int           `json:"batchSize" env:"REAPER_BATCH_SIZE,default=1000"`
+	MaxBatches int           `json:"maxBatches" env:"REAPER_MAX_BATCHES,default=10"`
+	Archive    bool          `json:"archive" env:"REAPER_ARCHIVE,default=false"`
+}
+
+// Cache : Redis cache of the urls, disabled when Addr is empty
+// TTL is capped by the expiration date of each url, NegativeTTL applies to unknown shorten URLs
+type Cache struct {
+	Addr        string        `json:"addr" env:"REDIS_ADDR"`
+	Password    string        `json:"password" env:"REDIS_PASSWORD"`
+	DB          int           `json:"db" env:"REDIS_DB,default=0"`
+	TTL         time.Duration `json:"ttl" env:"CACHE_TTL,default=10m"`
+	NegativeTTL time.Duration `json:"negativeTtl" env:"CACHE_NEGATIVE_TTL,default=30s"`
+}
+
+// RateLimit : Fixed window limits per client, kept in the Redis of the cache and disabled without it
+// A zero limit disables the limit of the route, APIKeyHeader identifies clients by this header instead of their IP
+// when it holds one of APIKeys
+type RateLimit struct {
+	CreateLimit    int           `json:"createLimit" env:"RATE_LIMIT_CREATE,default=10"`
+	CreateWindow   time.Duration `json:"createWindow" env:"RATE_LIMIT_CREATE_WINDOW,default=1m"`
+	RedirectLimit  int           `json:"redirectLimit" env:"RATE_LIMIT_REDIRECT,default=600"`
+	RedirectWindow time.Duration `json:"redirectWindow" env:"RATE_LIMIT_REDIRECT_WINDOW,default=1m"`
+	APIKeyHeader   string        `json:"apiKeyHeader" env:"RATE_LIMIT_API_KEY_HEADER"`
+	APIKeys        List          `json:"-" env:"RATE_LIMIT_API_KEYS"`
+
+	// AuthFailureLimit failed authentications of one IP within AuthFailureWindow block its next attempts
+	// until the window ends, 0 disables it
+	AuthFailureLimit  int           `json:"authFailureLimit" env:"RATE_LIMIT_AUTH_FAILURES,default=10"`
+	AuthFailureWindow time.Duration `json:"authFailureWindow" env:"RATE_LIMIT_AUTH_FAILURES_WINDOW,default=15m"`
+
+	// FailurePolicy is one of open, closed or local, used while Redis can not be reached
+	// local limits each of the Instances processes to its share of the limits
+	FailurePolicy    string        `json:"failurePolicy" env:"RATE_LIMIT_FAILURE_POLICY,default=open"`
+	Instances        int           `json:"instances" env:"RATE_LIMIT_INSTANCES,default=1"`
+	BreakerThreshold int           `json:"breakerThreshold" env:"RATE_LIMIT_BREAKER_THRESHOLD,default=5"`
+	BreakerTimeout   time.Duration `json:"breakerTimeout" env:"RATE_LIMIT_BREAKER_TIMEOUT,default=10s"`
+
+	// RulesHash is the Redis hash of the allow, deny and limit rules, managed with ratelimit-rules
+	RulesHash     string        `json:"rulesHash" env:"RATE_LIMIT_RULES_HASH,default=tinyurl:ratelimit:rules"`
+	RulesCacheTTL time.Duration `json:"rulesCacheTtl" env:"RATE_LIMIT_RULES_CACHE_TTL,default=10s"`
+}
+
+// Auth : HTTP basic authentication of the users
+// MaxVerifications is the number of passwords hashed at the same time, each takes 64 MiB, the number of CPUs when 0
+type Auth struct {
+	MaxVerifications int `json:"maxVerifications" env:"AUTH_MAX_VERIFICATIONS,default=4"`
+}
+
+// List : Environment value split on commas and spaces
+// go-env defaults can not contain commas, use spaces there
+type List []string
+
+func (l *List) UnmarshalEnvironmentValue(data string) error {
+	*l = strings.FieldsFunc(data, func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+
+	return nil
+}
+
 func ParseConfig() Config {
 	var config Config
 	_, err := env.UnmarshalFromEnviron(&config)
@@ -3,5 +3,65 @@ CREATE TABLE urls (
     original_url VARCHAR(2048),
     counter integer,
     expiration_date timestamp,
+    disabled boolean NOT NULL DEFAULT false,
+    -- Email of the user who created the url, empty for urls created before ownership
+    owner VARCHAR(256) NOT NULL DEFAULT '',
     PRIMARY KEY(shorten_url)
 );
+
+CREATE INDEX urls_owner_idx ON urls (owner);
+
+CREATE SEQUENCE urls_slug_seq;
+
+-- original_url can exceed the btree entry size, equality lookups only
+CREATE INDEX urls_original_url_idx ON urls USING hash (original_url);
+
+-- Used by the expiration reaper
+CREATE INDEX urls_expiration_date_idx ON urls (expiration_date);
+
+-- Expired urls moved by the reaper when archiving is enabled, a shorten URL can be archived several times
+CREATE TABLE urls_archive (
+    id BIGSERIAL,
+    shorten_url VARCHAR(256) NOT NULL,
+    original_url VARCHAR(2048),
+    counter integer,
+    expiration_date timestamp,
+    disabled boolean NOT NULL DEFAULT false,
+    owner VARCHAR(256) NOT NULL DEFAULT '',
+    archived_at timestamp NOT NULL,
+    PRIMARY KEY(id)
+);
+
+CREATE TABLE clicks (
+    id BIGSERIAL,
+    shorten_url VARCHAR(256) NOT NULL,
+    clicked_at timestamp NOT NULL,
+    referrer VARCHAR(2048),
+    user_agent VARCHAR(512),
+    client_ip VARCHAR(45),
+    accept_language VARCHAR(256),
+    PRIMARY KEY(id)
+);
+
+CREATE INDEX clicks_shorten_url_clicked_at_idx ON clicks (shorten_url, clicked_at);
+
+-- Clicks of the urls moved to urls_archive, archived_at matches the one of the url
+CREATE TABLE clicks_archive (
+    id BIGSERIAL,
+    shorten_url VARCHAR(256) NOT NULL,
+    clicked_at timestamp NOT NULL,
+    referrer VARCHAR(2048),
+    user_agent VARCHAR(512),
+    client_ip VARCHAR(45),
+    accept_language VARCHAR(256),
+    archived_at timestamp NOT NULL,
+    PRIMARY KEY(id)
+);
+
+-- Same table as postgresql/user/db.sql, users are managed by the postgresql/user module
+CREATE TABLE userAuthentication (
+    email VARCHAR(256),
+    password_hash VARCHAR(255),
+    last_login timestamp,
+    PRIMARY KEY(email)
+);
@@ -5,6 +5,7 @@ import (
 
 	"github.com/christapa/tinyurl/internal/tinyurl/domain"
 	tinyError "github.com/christapa/tinyurl/pkg/error"
+	"github.com/labstack/echo/v4"
 )
 
 func apiToDomainExpiration(expiration *int) time.Time {
@@ -15,6 +16,29 @@ func apiToDomainExpiration(expiration *int) time.Time {
 	return time.Unix(int64(*expiration), 0)
 }
 
+func apiToDomainAlias(alias *string) string {
+	if alias == nil {
+		return ""
+	}
+
+	return *alias
+}
+
+// apiToDomainUrlUpdate : An expiration date of 0 removes the expiration
+func apiToDomainUrlUpdate(body PatchSlugJSONBody) domain.UrlUpdate {
+	update := domain.UrlUpdate{OriginalURL: body.OriginalUrl}
+
+	if body.ExpirationDate != nil {
+		expiration := time.Time{}
+		if *body.ExpirationDate != 0 {
+			expiration = time.Unix(int64(*body.ExpirationDate), 0)
+		}
+		update.Expiration = &expiration
+	}
+
+	return update
+}
+
 func domainUrlToApi(url domain.Url) URL {
 	var expiration *int
 	if !url.Expiration.IsZero() {
@@ -26,7 +50,76 @@ func domainUrlToApi(url domain.Url) URL {
 		OriginalUrl:    url.OriginalURL,
 		ShortenedUrl:   url.ShortenURL,
 		ExpirationDate: expiration,
+		Disabled:       url.Disabled,
+	}
+}
+
+// defaultStatsRange : Range of the stats when from is not given
+const defaultStatsRange = 7 * 24 * time.Hour
+
+func apiToDomainStatsRange(params GetSlugStatsParams, now time.Time) (time.Time, time.Time, domain.StatsBucket, error) {
+	to := now.UTC()
+	if params.To != nil {
+		to = time.Unix(int64(*params.To), 0).UTC()
+	}
+
+	from := to.Add(-defaultStatsRange)
+	if params.From != nil {
+		from = time.Unix(int64(*params.From), 0).UTC()
 	}
+
+	bucket := domain.StatsBucketDay
+	if params.Bucket != nil {
+		parsedBucket, err := domain.ParseStatsBucket(string(*params.Bucket))
+		if err != nil {
+			return time.Time{}, time.Time{}, "", err
+		}
+		bucket = parsedBucket
+	}
+
+	return from, to, bucket, nil
+}
+
+func domainStatsToApi(stats domain.ClickStats) URLStats {
+	clicks := make([]ClickBucket, 0, len(stats.Clicks))
+	for _, count := range stats.Clicks {
+		clicks = append(clicks, ClickBucket{Start: int(count.Start.Unix()), Count: count.Count})
+	}
+
+	return URLStats{
+		Url:         domainUrlToApi(stats.Url),
+		TotalClicks: stats.Url.Counter,
+		From:        int(stats.From.Unix()),
+		To:          int(stats.To.Unix()),
+		Bucket:      URLStatsBucket(stats.Bucket),
+		Clicks:      clicks,
+		Referrers:   domainBreakdownToApi(stats.Referrers),
+		Browsers:    domainBreakdownToApi(stats.Browsers),
+		Devices:     domainBreakdownToApi(stats.Devices),
+		Languages:   domainBreakdownToApi(stats.Languages),
+	}
+}
+
+func domainBreakdownToApi(breakdown []domain.Breakdown) []BreakdownEntry {
+	entries := make([]BreakdownEntry, 0, len(breakdown))
+	for _, entry := range breakdown {
+		entries = append(entries, BreakdownEntry{Value: entry.Value, Count: entry.Count})
+	}
+
+	return entries
+}
+
+func newClickEvent(c echo.Context, slug string) domain.ClickEvent {
+	request := c.Request()
+
+	return domain.NewClickEvent(
+		slug,
+		time.Now().UTC(),
+		request.Referer(),
+		request.UserAgent(),
+		c.RealIP(),
+		request.Header.Get("Accept-Language"),
+	)
 }
 
 func GetHttpCode(e *tinyError.Error) int {
@@ -51,6 +144,10 @@ func CodeToHTTP(code tinyError.Code) int {
 		return 504
 	case tinyError.Internal:
 		return 500
+	case tinyError.Gone:
+		return 410
+	case tinyError.ResourceExhausted:
+		return 429
 	default:
 		return 500
 	}
@@ -73,6 +170,10 @@ func GetUserFriendlyMessage(e *tinyError.Error) string {
 		return "Deadline Exceeded"
 	case tinyError.Internal:
 		return "Internal Server Error"
+	case tinyError.Gone:
+		return "Gone"
+	case tinyError.ResourceExhausted:
+		return "Too Many Requests"
 	default:
 		return "Internal Server Error"
 	}
@@ -3,6 +3,7 @@ package http
 import (
 	"encoding/json"
 	"net/http"
+	"time"
 
 	"github.com/christapa/tinyurl/internal/tinyurl/usecases"
 	tinyError "github.com/christapa/tinyurl/pkg/error"
@@ -17,11 +18,15 @@ import (
 
 type HttpHandler struct {
 	Service usecases.URL
+	Clicks  usecases.Clicks
+	Stats   usecases.Stats
 }
 
-func NewHttpHandler(service usecases.URL) *HttpHandler {
+func NewHttpHandler(service usecases.URL, clicks usecases.Clicks, stats usecases.Stats) *HttpHandler {
 	return &HttpHandler{
 		Service: service,
+		Clicks:  clicks,
+		Stats:   stats,
 	}
 }
 
@@ -30,20 +35,41 @@ func (h HttpHandler) PostCreate(c echo.Context) error {
 	var body PostCreateJSONBody
 	err := c.Bind(&body)
 	if err != nil {
-		httpError(c, tinyError.New(tinyError.InvalidArgument, err.Error()))
+		return httpError(c, tinyError.New(tinyError.InvalidArgument, err.Error()))
 	}
 
-	url, err := h.Service.CreateShortenUrl(c.Request().Context(), body.OriginalUrl, apiToDomainExpiration(body.ExpirationDate))
+	url, created, err := h.Service.CreateShortenUrl(c.Request().Context(), owner(c), body.OriginalUrl, apiToDomainAlias(body.Alias), apiToDomainExpiration(body.ExpirationDate))
 	if err != nil {
 		logger.Errorf("Failed to create shorten URL: %v", err)
 		return httpError(c, err)
 
 	}
 
+	// The original URL was already shortened, the existing url is given back
+	if !created {
+		return c.JSON(http.StatusOK, domainUrlToApi(url))
+	}
+
 	return c.JSON(http.StatusCreated, domainUrlToApi(url))
 
 }
 
+// GET : /links
+func (h HttpHandler) GetLinks(c echo.Context) error {
+	urls, err := h.Service.ListUrls(c.Request().Context(), owner(c))
+	if err != nil {
+		logger.Errorf("Failed to list URLs: %v", err)
+		return httpError(c, err)
+	}
+
+	links := make([]URL, 0, len(urls))
+	for _, url := range urls {
+		links = append(links, domainUrlToApi(url))
+	}
+
+	return c.JSON(http.StatusOK, links)
+}
+
 // GET : /:<shortUrl>
 func (h HttpHandler) GetSlug(c echo.Context, slug string) error {
 	fullUrl, err := h.Service.GetOriginalUrl(c.Request().Context(), slug)
@@ -52,7 +78,74 @@ func (h HttpHandler) GetSlug(c echo.Context, slug string) error {
 		return httpError(c, err)
 	}
 
-	return c.Redirect(301, fullUrl)
+	h.Clicks.RecordClick(newClickEvent(c, slug))
+
+	// Not permanent, browsers would skip the disabled and updated urls and the clicks
+	return c.Redirect(http.StatusFound, fullUrl)
+}
+
+// PATCH : /:<shortUrl>
+func (h HttpHandler) PatchSlug(c echo.Context, slug string) error {
+	var body PatchSlugJSONBody
+	err := c.Bind(&body)
+	if err != nil {
+		return httpError(c, tinyError.New(tinyError.InvalidArgument, err.Error()))
+	}
+
+	url, err := h.Service.UpdateUrl(c.Request().Context(), owner(c), slug, apiToDomainUrlUpdate(body))
+	if err != nil {
+		logger.Errorf("Failed to update URL: %v", err)
+		return httpError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, domainUrlToApi(url))
+}
+
+// DELETE : /:<shortUrl>
+func (h HttpHandler) DeleteSlug(c echo.Context, slug string) error {
+	err := h.Service.DeleteUrl(c.Request().Context(), owner(c), slug)
+	if err != nil {
+		logger.Errorf("Failed to delete URL: %v", err)
+		return httpError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// POST : /:<shortUrl>/disable
+func (h HttpHandler) PostSlugDisable(c echo.Context, slug string) error {
+	return h.setDisabled(c, slug, true)
+}
+
+// POST : /:<shortUrl>/enable
+func (h HttpHandler) PostSlugEnable(c echo.Context, slug string) error {
+	return h.setDisabled(c, slug, false)
+}
+
+func (h HttpHandler) setDisabled(c echo.Context, slug string, disabled bool) error {
+	url, err := h.Service.SetUrlDisabled(c.Request().Context(), owner(c), slug, disabled)
+	if err != nil {
+		logger.Errorf("Failed to change the disabled flag of URL: %v", err)
+		return httpError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, domainUrlToApi(url))
+}
+
+// GET : /:<shortUrl>/stats
+func (h HttpHandler) GetSlugStats(c echo.Context, slug string, params GetSlugStatsParams) error {
+	from, to, bucket, err := apiToDomainStatsRange(params, time.Now())
+	if err != nil {
+		return httpError(c, err)
+	}
+
+	stats, err := h.Stats.GetURLStats(c.Request().Context(), owner(c), slug, from, to, bucket)
+	if err != nil {
+		logger.Errorf("Failed to get URL stats: %v", err)
+		return httpError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, domainStatsToApi(stats))
 }
 
 type ApplicationJsonErrorBody struct {
@@ -87,7 +180,7 @@ func newApplicationJsonErrorBodyFromTinyError(err *tinyError.Error, path string)
 		Message:  GetUserFriendlyMessage(err),
 		Title:    GetUserFriendlyMessage(err),
 		Status:   GetHttpCode(err),
-		Details:  GetUserFriendlyMessage(err),
+		Details:  err.HTTPError(),
 		Instance: path,
 	}
 }
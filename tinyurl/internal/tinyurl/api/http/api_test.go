@@ -0,0 +1,30 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/christapa/tinyurl/internal/tinyurl/usecases/mocks"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetSlugRedirectIsNotPermanent(t *testing.T) {
+	urlService := mocks.NewURL(t)
+	clicks := mocks.NewClicks(t)
+	stats := mocks.NewStats(t)
+
+	urlService.On("GetOriginalUrl", mock.Anything, "rGu2aeQO").Return("https://www.google.com", nil)
+	clicks.On("RecordClick", mock.Anything).Return()
+
+	e := echo.New()
+	RegisterHandlers(e, NewHttpHandler(urlService, clicks, stats))
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/rGu2aeQO", nil))
+
+	assert.Equal(t, http.StatusFound, rec.Code, "A permanent redirect would be cached by the browsers")
+	assert.Equal(t, "https://www.google.com", rec.Header().Get(echo.HeaderLocation))
+}
@@ -0,0 +1,93 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/christapa/tinyurl/internal/tinyurl/usecases"
+	tinyError "github.com/christapa/tinyurl/pkg/error"
+	"github.com/christapa/tinyurl/pkg/logger"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// ownerContextKey : Email of the authenticated caller
+const ownerContextKey = "owner"
+
+// AuthFailures : Failed authentications counted per client IP
+type AuthFailures interface {
+	// Blocked returns the time left before the client can authenticate again, 0 when it is not blocked
+	Blocked(ctx context.Context, ip string) (time.Duration, error)
+	Fail(ctx context.Context, ip string) error
+}
+
+// NewAuthMiddleware : HTTP basic authentication against the users table
+// Redirections and the health check are public, every other request needs a user
+// Clients with too many failures are answered with 429 before their password is hashed, failures is optional
+// Errors of failures are logged and let the request through, as for the rate limits
+func NewAuthMiddleware(users usecases.Users, failures AuthFailures) echo.MiddlewareFunc {
+	basicAuth := middleware.BasicAuthWithConfig(middleware.BasicAuthConfig{
+		Skipper: isPublicRequest,
+		Validator: func(email string, password string, c echo.Context) (bool, error) {
+			ok, err := users.Authenticate(c.Request().Context(), email, password)
+			if err != nil {
+				logger.Errorf("Failed to authenticate user: %v", err)
+				return false, err
+			}
+
+			if ok {
+				c.Set(ownerContextKey, email)
+			} else if failures != nil {
+				if err := failures.Fail(c.Request().Context(), c.RealIP()); err != nil {
+					logger.Errorf("Failed to count the failed authentication of %s: %v", c.RealIP(), err)
+				}
+			}
+
+			return ok, nil
+		},
+	})
+
+	if failures == nil {
+		return basicAuth
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		authenticate := basicAuth(next)
+
+		return func(c echo.Context) error {
+			if isPublicRequest(c) {
+				return next(c)
+			}
+
+			retryAfter, err := failures.Blocked(c.Request().Context(), c.RealIP())
+			if err != nil {
+				logger.Errorf("Failed to check the failed authentications of %s: %v", c.RealIP(), err)
+				return authenticate(c)
+			}
+
+			if retryAfter > 0 {
+				c.Response().Header().Set(echo.HeaderRetryAfter, ceilSeconds(retryAfter))
+				return httpError(c, tinyError.New(tinyError.ResourceExhausted, "too many failed authentications"))
+			}
+
+			return authenticate(c)
+		}
+	}
+}
+
+// isPublicRequest : Only the redirections and the health check, the route is the one matched by echo
+func isPublicRequest(c echo.Context) bool {
+	method := c.Request().Method
+	if method != http.MethodGet && method != http.MethodHead {
+		return false
+	}
+
+	return c.Path() == "/:slug" || c.Path() == "/health"
+}
+
+// owner : Empty when the request was not authenticated
+func owner(c echo.Context) string {
+	email, _ := c.Get(ownerContextKey).(string)
+	return email
+}
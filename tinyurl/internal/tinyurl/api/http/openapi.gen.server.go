@@ -16,9 +16,27 @@ type ServerInterface interface {
 	// Create a new shortened URL
 	// (POST /create)
 	PostCreate(ctx echo.Context) error
+	// List the shortened URLs of the authenticated user
+	// (GET /links)
+	GetLinks(ctx echo.Context) error
+	// Delete a shortened URL
+	// (DELETE /{slug})
+	DeleteSlug(ctx echo.Context, slug string) error
 	// Redirect to the original URL
 	// (GET /{slug})
 	GetSlug(ctx echo.Context, slug string) error
+	// Change the destination or the expiration date of a shortened URL
+	// (PATCH /{slug})
+	PatchSlug(ctx echo.Context, slug string) error
+	// Disable the redirect of a shortened URL
+	// (POST /{slug}/disable)
+	PostSlugDisable(ctx echo.Context, slug string) error
+	// Enable the redirect of a shortened URL
+	// (POST /{slug}/enable)
+	PostSlugEnable(ctx echo.Context, slug string) error
+	// Get the click analytics of a shortened URL
+	// (GET /{slug}/stats)
+	GetSlugStats(ctx echo.Context, slug string, params GetSlugStatsParams) error
 }
 
 // ServerInterfaceWrapper converts echo contexts to parameters.
@@ -30,11 +48,42 @@ type ServerInterfaceWrapper struct {
 func (w *ServerInterfaceWrapper) PostCreate(ctx echo.Context) error {
 	var err error
 
+	ctx.Set(BasicAuthScopes, []string{})
+
 	// Invoke the callback with all the unmarshaled arguments
 	err = w.Handler.PostCreate(ctx)
 	return err
 }
 
+// GetLinks converts echo context to params.
+func (w *ServerInterfaceWrapper) GetLinks(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BasicAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetLinks(ctx)
+	return err
+}
+
+// DeleteSlug converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteSlug(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "slug" -------------
+	var slug string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "slug", ctx.Param("slug"), &slug, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter slug: %s", err))
+	}
+
+	ctx.Set(BasicAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeleteSlug(ctx, slug)
+	return err
+}
+
 // GetSlug converts echo context to params.
 func (w *ServerInterfaceWrapper) GetSlug(ctx echo.Context) error {
 	var err error
@@ -51,6 +100,101 @@ func (w *ServerInterfaceWrapper) GetSlug(ctx echo.Context) error {
 	return err
 }
 
+// PatchSlug converts echo context to params.
+func (w *ServerInterfaceWrapper) PatchSlug(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "slug" -------------
+	var slug string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "slug", ctx.Param("slug"), &slug, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter slug: %s", err))
+	}
+
+	ctx.Set(BasicAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PatchSlug(ctx, slug)
+	return err
+}
+
+// PostSlugDisable converts echo context to params.
+func (w *ServerInterfaceWrapper) PostSlugDisable(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "slug" -------------
+	var slug string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "slug", ctx.Param("slug"), &slug, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter slug: %s", err))
+	}
+
+	ctx.Set(BasicAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostSlugDisable(ctx, slug)
+	return err
+}
+
+// PostSlugEnable converts echo context to params.
+func (w *ServerInterfaceWrapper) PostSlugEnable(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "slug" -------------
+	var slug string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "slug", ctx.Param("slug"), &slug, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter slug: %s", err))
+	}
+
+	ctx.Set(BasicAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostSlugEnable(ctx, slug)
+	return err
+}
+
+// GetSlugStats converts echo context to params.
+func (w *ServerInterfaceWrapper) GetSlugStats(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "slug" -------------
+	var slug string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "slug", ctx.Param("slug"), &slug, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter slug: %s", err))
+	}
+
+	ctx.Set(BasicAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetSlugStatsParams
+	// ------------- Optional query parameter "from" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "from", ctx.QueryParams(), &params.From)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter from: %s", err))
+	}
+
+	// ------------- Optional query parameter "to" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "to", ctx.QueryParams(), &params.To)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter to: %s", err))
+	}
+
+	// ------------- Optional query parameter "bucket" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "bucket", ctx.QueryParams(), &params.Bucket)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter bucket: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetSlugStats(ctx, slug, params)
+	return err
+}
+
 // This is a simple interface which specifies echo.Route addition functions which
 // are present on both echo.Echo and echo.Group, since we want to allow using
 // either of them for path registration
@@ -80,6 +224,12 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 	}
 
 	router.POST(baseURL+"/create", wrapper.PostCreate)
+	router.GET(baseURL+"/links", wrapper.GetLinks)
+	router.DELETE(baseURL+"/:slug", wrapper.DeleteSlug)
 	router.GET(baseURL+"/:slug", wrapper.GetSlug)
+	router.PATCH(baseURL+"/:slug", wrapper.PatchSlug)
+	router.POST(baseURL+"/:slug/disable", wrapper.PostSlugDisable)
+	router.POST(baseURL+"/:slug/enable", wrapper.PostSlugEnable)
+	router.GET(baseURL+"/:slug/stats", wrapper.GetSlugStats)
 
 }
@@ -18,18 +18,42 @@ import (
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/9RVTW/bSAz9KwPuHgXL+TgsdMu2QGHAB8OND0WQw0SipAmk4XRI2TEM/fdiRnFs2Q6C",
-	"FL30pITg8D0+PtI7yKl1ZNEKQ7YDzmtsdfxztZyHj/Pk0IvBGMQXZ7wWQ/arFgyRAjn3xoUQZLCy5kWJ",
-	"aZFFt04ZqxhzsgWrkrySGtWhgiq0oKIyhrkmL2ixUKvlfAIJyNYhZGCsYIUe+gTIm8pY3ax8cw58X6Pa",
-	"J4QSkAC+6NY1oUgt4jhL081mM6mIqgYnObUHEBZvbBUw3mi8CzIiehGloVw3NbFkt9Oraap/XC/W/52D",
-	"9Ql4/NkZjwVkD2PkcbOPb2/p6RlzgT48Nrakc4p3i1mUOveoxdhKaVsoj+INrsO/I/ochTYS6d8buw1B",
-	"dbeYQQJr9DyUvJpMJ9M4AIdWOwMZ3MRQAk5LHX2RRrxoCEcs4RtsE+c8KyCDBbF8GXKGvpHlfyq2ITMn",
-	"K2jjI+1cY/L4LH3mAL/35F/sRSWkno7KQgIl+VYLZNB586E1PvbCIVt8hzHAjiwPQl1Pp5+S+V+PJWTw",
-	"T3q4DenrYUiD6SPkidbL+ZFs3OU5Mpdd02yDWrefJDCec4vMusJh5Ptlm9m1bkyckHoV85KMF7QaEz+u",
-	"w11ghUV8yV3bar+FDAbbKq0sbk7WPySmO266qg/0Krzg/G8o35uuitvidYuCniF7uHhbmq56M+fpoTEh",
-	"KywcJGB1Gxsd6o6nnxwJedDr3Sv0eGKWm+n1uaOXWBiPeTwoQpHeybGtURexsR3MaRjshY0cdsG/VlNC",
-	"cMz2g6Xoo5Nu/7STAilLokrqbPF7JhqXGLtneWj2XLdYi9Gv957owj1555cE+sf+VwAAAP//a/hSKbAH",
-	"AAA=",
+	"H4sIAAAAAAAC/+1aW3MbtxX+KxgmD8mEIimZ0yR6k2U3kxmlzcjWQ+qoGXAXJGHtAlsAK4r18L/nO8De",
+	"uZSsa6xWLxK5l3M/33cA4tMg0mmmlVDODg4/DWy0FCn3H18bwS9ivVJvlTNrupIZnQnjpPD3I50rRx9i",
+	"YSMjMye1GhwO/pGnM2GYnrMokdGFZSvplswtpWWXPMnFYDhw60zgSamcWAgz2AwH4c6WrJ+MzjMRhxeH",
+	"zObRknHLODNiLowRZojPM6NXFhrnPJXJmmmDawlXi5wvSJm44mmWkL6/S7ymr2oDrDNSLQYbGGDEf3Lc",
+	"jgeHHwalmcHB8+pxPfsoIkfmHpNnr/PoQri7x0UqREWwWRDTFxXruOmRdKbkFXMyFbifZiTHikir2JJw",
+	"EunfK78E+UN67Oz98ahHUcf9oPU6989OT7bdjqXls4REdO19U9xheA/JU3aFOEz3JzDJOsFjshS6oT9y",
+	"lI9K40zrRHBFKsVVJg0ngW+4E7eJyRwFQXGoJbAYIqpYLbVxQgXrRr1p0EYupOLJmUm2Fb+HjPIBEtGq",
+	"uKVzmT0cj1er1Wih9SIRI7TbdgEi1aUZO5W0DO3VkuiIJ0tt3eF0sj8Z898Ofr384cZqb2luOzusk7qj",
+	"DN45HnCjXQtFS9oeT3TGctzaQ3MqF3qWXhoOpBOpf+FrdCme/GpcI9O4gKVxB5M2lVXcGO6/z6qu7EIJ",
+	"V3nCjXTrMvVFH1LVoFpMMEOoPKWwLHVuKACQOhykWrllIwZ13oKMbXXHQTZiUjQg05ciFCIMWQDMRJrB",
+	"lHCTwCBK8hiB/sxANBGoJwqxuJSRuN6u8AyjVw/xxV44nQ1ZqmcygXmO8g7YmGlHkJqrC4WwP1ye5gaN",
+	"cE9oKyJZxq6/e0sq2FGMmZEpN+uKMQAYsMyLP4oikbm9k+IOWwKrhHm4EJQktsOy6vaQfRPA8VsPZg1a",
+	"1bmrHns4u5y+Q2KEijtpEVfXpcVpx5PjHd1Tc2XJC5ZZpFl4+cAdtsIYUCFXr4I8AOl1oSAg7cJh7nGv",
+	"aV1Rqz4uFb5Und9M47AGvroFmxW4jaIE/SLKCZbekVUFfnIro6McmLMVmrcplwlINGYZt3aljQ8796AK",
+	"Xd41z50kog4McQS0QZ1U8578Hv36cyguVAeRsNdgBHAObuBri32sT6l0nn3eS7X2KYEIXAbK2SByfzQZ",
+	"TTx/ZkLxTOLSK39pOMi4W3pHx16f5/MMxEX/KRmostc6XodpCikN8xTPMsTcU/j4oyUd5by6zUA8kbwP",
+	"/nLr0N02yRcUsbg5gnAGShIYEXAZhTJip2gv4EE5daL9E0yyZZAE4wa1LhD3y1DkNSPbjPhhz/KE5siU",
+	"X50ItaBs/m2Kr1KVX1/5UDhhyLZ/fzja+xff++9k78c/9s6/+7pvTHg2YxBzms0aYkfsnwrDeZbPkEJG",
+	"1fiN/ZZYB8XmrbE+nNxDLqyYrXFzzvPEkTEwPuWoAXSnbAf0YDL94aYJp2l4Twe2nnYmF/6CBVjYUEsH",
+	"k8mtKvFmyPEM3cobIsYTlFW8rmM2LBImrdtqQYblFNozNwR/EHcw2X8KG2sb0BVANzvPk8STxvSWQWq3",
+	"KwrW0nINH+su+llhJSaDu0UF9KW6J59tw5tybE5WlbGFEZhyUpSaA6papngaKMa/ELrE5Ald4zSsyiRE",
+	"e3pttAup393kdKG17fSZ4sB9SCVpTWq7jce/SGupZNDzsnAemBWTVA6F3oMfH8sDD73s9yYI/j6gei0L",
+	"3PELoe7mGMFMkN+VN2QFwBXNsdVNYR+Cs1jOwde0+OhCIVGex0JCdIP2+ugHD+kn7OnBowWMKIclMpVk",
+	"UiREfNe0v9eapRx0XHAoJsdTUPh672gOjmELeQk6Cx4GUgBIr7iktgqzrTfsFOackDV7/u821p8W0hHj",
+	"RK88iSKuK6kwVZYjiPe9Z6OhYc+24HeFWTOBdkc+mBKr0hef04IdrleyaQ5VwP/WOPXhfAMKsHlKQz8N",
+	"BAWVe1XtJTaJGSdShQF1IYrp5B7E8FnzuVfdHcq3c/2uNZAxzPOBNf3CFokJnP3cgeoWmTwBTW6PMtX6",
+	"pGVsmJZ9gj/RLLgJXmLBG0ZRbkAEzvfDh969GJofy4mquzEj6SmacPGZCIVCgucH3TGjWcQN9Ny1a3O+",
+	"NZVMe+Y/gF/w43+Gp149mgeI1UwkWi08EHKl4Y8pF1J3JKfrhHp3po/pDrShLHN1x3S0Rdym+d74mgOM",
+	"diB0WOLms2ipV5ODPrardqYpo257v7fBnSc6pLK/M/F6uZ/B/GZCbW1nhdO1dHNj7dxpwn74moGZ+5PH",
+	"LHGMAdWm9J0NLiUQYUoX2NO8THnPb8qrAOi07qvtFt34bZZo+WUD0UNse91/f4iS0VkYDdkE+Ul1WVf1",
+	"7TtuFJGODobefofnS93GybOYV+PXo0Fhd+0qq9+TM27vuU/S2sdDWXR0vQyWL4Plkw2Wx0v6MckXdmOz",
+	"mO3ezOZ9q/iwyBsXtN/8teGZLPWeBLmqueqlwV8a/MlWjqHowg/H5RB3Qx8L9dLGu9s4ROeli1+6+Om6",
+	"+K26dRPb8ujYl7xDNHyAA0oj9ib8ku0r4ntMKutq8ev0qDQeyz+zrq0vzpxct8y+9xmdtmVKr3YZ09ms",
+	"utmUzzptN2JHjqVAcPb9dFqdhAunKsIv27vsqQ7h1DYVpwVwMxzZu9U5vseG6nBMsqfj/BEjoARP1gBS",
+	"23sa45FXkr4SaP3otGZIGb5Qk1HcypzcbzkZFOhS2gsv/X/x0l/GST8JVwNPu8e2ecnLpZ/9A/v4w4M7",
+	"DlYPNuebPwEzB0ZPNzEAAA==",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
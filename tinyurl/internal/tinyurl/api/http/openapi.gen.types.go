@@ -3,8 +3,47 @@
 // Code generated by github.com/deepmap/oapi-codegen/v2 version v2.1.0 DO NOT EDIT.
 package http
 
+const (
+	BasicAuthScopes = "basicAuth.Scopes"
+)
+
+// Defines values for URLStatsBucket.
+const (
+	URLStatsBucketDay   URLStatsBucket = "day"
+	URLStatsBucketHour  URLStatsBucket = "hour"
+	URLStatsBucketMonth URLStatsBucket = "month"
+)
+
+// Defines values for GetSlugStatsParamsBucket.
+const (
+	GetSlugStatsParamsBucketDay   GetSlugStatsParamsBucket = "day"
+	GetSlugStatsParamsBucketHour  GetSlugStatsParamsBucket = "hour"
+	GetSlugStatsParamsBucketMonth GetSlugStatsParamsBucket = "month"
+)
+
+// BreakdownEntry defines model for BreakdownEntry.
+type BreakdownEntry struct {
+	// Count Number of clicks with this value
+	Count int `json:"count"`
+
+	// Value Grouped value, such as a referrer, a browser family or a language
+	Value string `json:"value"`
+}
+
+// ClickBucket defines model for ClickBucket.
+type ClickBucket struct {
+	// Count Number of clicks in the bucket
+	Count int `json:"count"`
+
+	// Start Unix timestamp in seconds of the start of the bucket, in UTC.
+	Start int `json:"start"`
+}
+
 // URL defines model for URL.
 type URL struct {
+	// Disabled Disabled URLs answer 410 instead of redirecting
+	Disabled bool `json:"disabled"`
+
 	// ExpirationDate Unix timestamp in seconds for the expiration date of the shortened URL.
 	ExpirationDate *int `json:"expirationDate,omitempty"`
 
@@ -15,8 +54,45 @@ type URL struct {
 	ShortenedUrl string `json:"shortenedUrl"`
 }
 
+// URLStats defines model for URLStats.
+type URLStats struct {
+	// Browsers Top user-agent families
+	Browsers []BreakdownEntry `json:"browsers"`
+
+	// Bucket Granularity of the clicks time series
+	Bucket URLStatsBucket `json:"bucket"`
+
+	// Clicks Clicks per bucket over the range, empty buckets included
+	Clicks []ClickBucket `json:"clicks"`
+
+	// Devices Clicks per device type: desktop, mobile, tablet, bot or unknown
+	Devices []BreakdownEntry `json:"devices"`
+
+	// From Unix timestamp in seconds of the start of the range, included.
+	From int `json:"from"`
+
+	// Languages Top primary languages from the Accept-Language header
+	Languages []BreakdownEntry `json:"languages"`
+
+	// Referrers Top referrers, (direct) for clicks without referrer
+	Referrers []BreakdownEntry `json:"referrers"`
+
+	// To Unix timestamp in seconds of the end of the range, excluded.
+	To int `json:"to"`
+
+	// TotalClicks Number of redirects since the URL was shortened
+	TotalClicks int `json:"totalClicks"`
+	Url         URL `json:"url"`
+}
+
+// URLStatsBucket Granularity of the clicks time series
+type URLStatsBucket string
+
 // PostCreateJSONBody defines parameters for PostCreate.
 type PostCreateJSONBody struct {
+	// Alias Custom slug used instead of a generated one. Routes such as health or create are reserved.
+	Alias *string `json:"alias,omitempty"`
+
 	// ExpirationDate Unix timestamp in seconds for the expiration date of the shortened URL.
 	ExpirationDate *int `json:"expirationDate,omitempty"`
 
@@ -24,5 +100,32 @@ type PostCreateJSONBody struct {
 	OriginalUrl string `json:"originalUrl"`
 }
 
+// PatchSlugJSONBody defines parameters for PatchSlug.
+type PatchSlugJSONBody struct {
+	// ExpirationDate Unix timestamp in seconds for the new expiration date, 0 removes the expiration.
+	ExpirationDate *int `json:"expirationDate,omitempty"`
+
+	// OriginalUrl The new original URL
+	OriginalUrl *string `json:"originalUrl,omitempty"`
+}
+
+// GetSlugStatsParams defines parameters for GetSlugStats.
+type GetSlugStatsParams struct {
+	// From Unix timestamp in seconds of the start of the range. Defaults to 7 days before to.
+	From *int `form:"from,omitempty" json:"from,omitempty"`
+
+	// To Unix timestamp in seconds of the end of the range. Defaults to now.
+	To *int `form:"to,omitempty" json:"to,omitempty"`
+
+	// Bucket Granularity of the clicks time series. At most 744 buckets are returned.
+	Bucket *GetSlugStatsParamsBucket `form:"bucket,omitempty" json:"bucket,omitempty"`
+}
+
+// GetSlugStatsParamsBucket defines parameters for GetSlugStats.
+type GetSlugStatsParamsBucket string
+
 // PostCreateJSONRequestBody defines body for PostCreate for application/json ContentType.
 type PostCreateJSONRequestBody PostCreateJSONBody
+
+// PatchSlugJSONRequestBody defines body for PatchSlug for application/json ContentType.
+type PatchSlugJSONRequestBody PatchSlugJSONBody
@@ -0,0 +1,138 @@
+package http
+
+import (
+	"crypto/sha256"
+	"math"
+	"strconv"
+	"time"
+
+	ratelimit "github.com/christapa/testContainers/redis/ratelimiter"
+	tinyError "github.com/christapa/tinyurl/pkg/error"
+	"github.com/christapa/tinyurl/pkg/logger"
+	"github.com/labstack/echo/v4"
+)
+
+// rateLimitKeyPrefix : Keys are tinyurl:ratelimit:<method> <route>:<client>
+// API keys are credentials, the client of a key is its ratelimit.HashAPIKey so key names never hold one
+const rateLimitKeyPrefix = "tinyurl:ratelimit:"
+
+// RateLimitRule : Limit of one route, Path is the echo route such as /:slug
+type RateLimitRule struct {
+	Method  string
+	Path    string
+	Limiter ratelimit.Limiter
+}
+
+func NewRateLimitRule(method string, path string, limiter ratelimit.Limiter) RateLimitRule {
+	return RateLimitRule{
+		Method:  method,
+		Path:    path,
+		Limiter: limiter,
+	}
+}
+
+// APIKeys : Keys accepted in the API key header, looked up by their SHA-256
+type APIKeys map[[sha256.Size]byte]struct{}
+
+func NewAPIKeys(keys []string) APIKeys {
+	apiKeys := make(APIKeys, len(keys))
+	for _, key := range keys {
+		apiKeys[sha256.Sum256([]byte(key))] = struct{}{}
+	}
+
+	return apiKeys
+}
+
+func (k APIKeys) Valid(key string) bool {
+	_, ok := k[sha256.Sum256([]byte(key))]
+	return ok
+}
+
+// NewRateLimitMiddleware : Requests over the limit of their route are answered with 429
+// Clients are identified by the apiKeyHeader when given and sent with one of the apiKeys, by their IP otherwise
+// The IP is the one of echo.Echo.IPExtractor, which must only trust the configured proxies
+// Redis errors let the request through, the limit is not worth an outage
+func NewRateLimitMiddleware(rules []RateLimitRule, apiKeyHeader string, apiKeys APIKeys) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			rule, found := findRateLimitRule(rules, c.Request().Method, c.Path())
+			if !found {
+				return next(c)
+			}
+
+			subject := rateLimitSubject(c, apiKeyHeader, apiKeys)
+			kind, client := "ip", subject.IP
+			// The kind keeps an API key and an IP with the same value apart
+			if subject.APIKey != "" {
+				kind, client = "key", ratelimit.HashAPIKey(subject.APIKey)
+			}
+
+			key := rateLimitKeyPrefix + rule.Method + " " + rule.Path + ":" + kind + ":" + client
+			result, err := allowRateLimit(c, rule.Limiter, subject, key)
+			if err != nil {
+				logger.Errorf("Failed to check the rate limit of %s: %v", key, err)
+				return next(c)
+			}
+
+			if result.Denied {
+				return httpError(c, tinyError.New(tinyError.PermissionDenied, "client is denied"))
+			}
+
+			setRateLimitHeaders(c, result)
+
+			if !result.Allowed {
+				c.Response().Header().Set(echo.HeaderRetryAfter, ceilSeconds(result.RetryAfter))
+				return httpError(c, tinyError.New(tinyError.ResourceExhausted, "rate limit exceeded"))
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// setRateLimitHeaders : RateLimit-Reset is a number of seconds, as Retry-After
+// Results without limit, given when failing open, have no header
+func setRateLimitHeaders(c echo.Context, result ratelimit.Result) {
+	if result.Limit == 0 {
+		return
+	}
+
+	header := c.Response().Header()
+	header.Set("RateLimit-Limit", strconv.FormatInt(result.Limit, 10))
+	header.Set("RateLimit-Remaining", strconv.FormatInt(result.Remaining, 10))
+	header.Set("RateLimit-Reset", ceilSeconds(time.Until(result.ResetAt)))
+}
+
+func ceilSeconds(duration time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(max(duration, 0).Seconds())))
+}
+func findRateLimitRule(rules []RateLimitRule, method string, path string) (RateLimitRule, bool) {
+	for _, rule := range rules {
+		if rule.Method == method && rule.Path == path {
+			return rule, true
+		}
+	}
+
+	return RateLimitRule{}, false
+}
+
+// allowRateLimit : Limiters with override rules match them against the API key and the IP
+func allowRateLimit(c echo.Context, limiter ratelimit.Limiter, subject ratelimit.Subject, key string) (ratelimit.Result, error) {
+	if subjectLimiter, ok := limiter.(ratelimit.SubjectLimiter); ok {
+		return subjectLimiter.AllowSubject(c.Request().Context(), subject, key)
+	}
+
+	return limiter.Allow(c.Request().Context(), key)
+}
+
+// rateLimitSubject : Unknown keys are ignored, a client can not pick its own counter nor rule
+func rateLimitSubject(c echo.Context, apiKeyHeader string, apiKeys APIKeys) ratelimit.Subject {
+	subject := ratelimit.Subject{IP: c.RealIP()}
+	if apiKeyHeader != "" {
+		if apiKey := c.Request().Header.Get(apiKeyHeader); apiKey != "" && apiKeys.Valid(apiKey) {
+			subject.APIKey = apiKey
+		}
+	}
+
+	return subject
+}
@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	MinAliasLength = 3
+	MaxAliasLength = 64
+)
+
+var aliasPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// reservedShortenURLs : Routes served by the API, a shorten URL must not shadow them
+var reservedShortenURLs = []string{
+	"admin",
+	"api",
+	"create",
+	"debug",
+	"health",
+	"links",
+	"login",
+	"logout",
+	"metrics",
+	"static",
+}
+
+// NewAliasURL : Url whose shorten URL is chosen by the user
+func NewAliasURL(validator URLValidator, alias string, originalURL string, expiration time.Time) (Url, error) {
+	if err := ValidateAlias(alias); err != nil {
+		return Url{}, err
+	}
+
+	return NewURL(validator, alias, originalURL, expiration)
+}
+
+func ValidateAlias(alias string) error {
+	if len(alias) < MinAliasLength || len(alias) > MaxAliasLength {
+		return NewInvalidInputError(fmt.Sprintf("alias must be between %d and %d characters", MinAliasLength, MaxAliasLength))
+	}
+
+	if !aliasPattern.MatchString(alias) {
+		return NewInvalidInputError("alias may only contain letters, digits, '-' and '_'")
+	}
+
+	if IsReservedShortenURL(alias) {
+		return NewInvalidInputError(fmt.Sprintf("alias %q is reserved", alias))
+	}
+
+	return nil
+}
+
+// IsReservedShortenURL : Comparison is case-insensitive so /Health can not be confused with /health
+func IsReservedShortenURL(shortenURL string) bool {
+	return slices.ContainsFunc(reservedShortenURLs, func(reserved string) bool {
+		return strings.EqualFold(reserved, shortenURL)
+	})
+}
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewAliasURL(t *testing.T) {
+	tests := []struct {
+		name  string
+		alias string
+		want  Url
+		err   error
+	}{
+		{
+			name:  "Valid alias",
+			alias: "spring-sale",
+			want: Url{
+				ShortenURL:  "spring-sale",
+				OriginalURL: "https://www.google.com",
+			},
+			err: nil,
+		},
+		{
+			name:  "Too short alias",
+			alias: "ab",
+			want:  Url{},
+			err:   NewInvalidInputError("alias must be between 3 and 64 characters"),
+		},
+		{
+			name:  "Too long alias",
+			alias: strings.Repeat("a", MaxAliasLength+1),
+			want:  Url{},
+			err:   NewInvalidInputError("alias must be between 3 and 64 characters"),
+		},
+		{
+			name:  "Invalid characters",
+			alias: "spring/sale",
+			want:  Url{},
+			err:   NewInvalidInputError("alias may only contain letters, digits, '-' and '_'"),
+		},
+		{
+			name:  "Reserved alias",
+			alias: "Health",
+			want:  Url{},
+			err:   NewInvalidInputError(`alias "Health" is reserved`),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewAliasURL(NewURLValidator(DefaultValidationRules()), tt.alias, "https://www.google.com", time.Time{})
+			if err != nil && !errors.Is(err, tt.err) {
+				t.Errorf("NewAliasURL() = %v, want %v", err, tt.err)
+			}
+
+			if err == nil && tt.err != nil {
+				t.Errorf("NewAliasURL() = nil, want %v", tt.err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewAliasURL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
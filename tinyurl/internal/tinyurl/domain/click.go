@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"context"
+	"net/netip"
+	"strings"
+	"time"
+)
+
+// Widths of the clicks columns, longer values are truncated
+const (
+	maxReferrerLength       = 2048
+	maxUserAgentLength      = 512
+	maxAcceptLanguageLength = 256
+)
+
+// ClickEvent : One redirect served for a shorten URL
+type ClickEvent struct {
+	ShortenURL     string
+	Timestamp      time.Time
+	Referrer       string
+	UserAgent      string
+	ClientIP       string
+	AcceptLanguage string
+}
+
+type ClickRepository interface {
+	StoreClicks(ctx context.Context, events []ClickEvent) error
+}
+
+func NewClickEvent(shortenURL string, timestamp time.Time, referrer string, userAgent string, clientIP string, acceptLanguage string) ClickEvent {
+	return ClickEvent{
+		ShortenURL:     shortenURL,
+		Timestamp:      timestamp,
+		Referrer:       truncate(referrer, maxReferrerLength),
+		UserAgent:      truncate(userAgent, maxUserAgentLength),
+		ClientIP:       normalizeIP(clientIP),
+		AcceptLanguage: truncate(acceptLanguage, maxAcceptLanguageLength),
+	}
+}
+
+// Anonymize : Drop the host part of the client IP, /24 for IPv4 and /48 for IPv6
+func (c ClickEvent) Anonymize() ClickEvent {
+	c.ClientIP = AnonymizeIP(c.ClientIP)
+	return c
+}
+
+// normalizeIP : Unparsable addresses are dropped entirely, like in AnonymizeIP,
+// so the value always fits the client_ip column
+func normalizeIP(ip string) string {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return ""
+	}
+
+	return addr.Unmap().WithZone("").String()
+}
+
+// AnonymizeIP : Unparsable addresses are dropped entirely
+func AnonymizeIP(ip string) string {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return ""
+	}
+
+	addr = addr.Unmap().WithZone("")
+
+	bits := 48
+	if addr.Is4() {
+		bits = 24
+	}
+
+	prefix, err := addr.Prefix(bits)
+	if err != nil {
+		return ""
+	}
+
+	return prefix.Addr().String()
+}
+
+// truncate : Cut at a rune boundary so the value stays valid UTF-8
+func truncate(value string, length int) string {
+	if len(value) <= length {
+		return value
+	}
+
+	return strings.ToValidUTF8(value[:length], "")
+}
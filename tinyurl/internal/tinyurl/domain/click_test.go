@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAnonymizeIP(t *testing.T) {
+	tests := []struct {
+		name string
+		ip   string
+		want string
+	}{
+		{
+			name: "IPv4",
+			ip:   "203.0.113.42",
+			want: "203.0.113.0",
+		},
+		{
+			name: "IPv6",
+			ip:   "2001:db8:85a3:8d3:1319:8a2e:370:7348",
+			want: "2001:db8:85a3::",
+		},
+		{
+			name: "IPv4 mapped IPv6",
+			ip:   "::ffff:203.0.113.42",
+			want: "203.0.113.0",
+		},
+		{
+			name: "Invalid IP",
+			ip:   "unknown",
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AnonymizeIP(tt.ip); got != tt.want {
+				t.Errorf("AnonymizeIP() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewClickEventClientIP(t *testing.T) {
+	tests := []struct {
+		name string
+		ip   string
+		want string
+	}{
+		{
+			name: "IPv4",
+			ip:   "203.0.113.42",
+			want: "203.0.113.42",
+		},
+		{
+			name: "IPv4 mapped IPv6",
+			ip:   "::ffff:203.0.113.42",
+			want: "203.0.113.42",
+		},
+		{
+			name: "IPv6 with zone",
+			ip:   "fe80::1%" + strings.Repeat("a", 64),
+			want: "fe80::1",
+		},
+		{
+			name: "Invalid IP",
+			ip:   strings.Repeat("1", 64),
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewClickEvent("rGu2aeQO", time.Now(), "", "", tt.ip, "").ClientIP; got != tt.want {
+				t.Errorf("ClientIP = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewClickEventTruncates(t *testing.T) {
+	event := NewClickEvent("rGu2aeQO", time.Now(), "https://www.google.com/"+strings.Repeat("é", maxReferrerLength), "", "", "")
+
+	if len(event.Referrer) > maxReferrerLength {
+		t.Errorf("Referrer length = %v, want at most %v", len(event.Referrer), maxReferrerLength)
+	}
+
+	if !strings.HasPrefix(event.Referrer, "https://www.google.com/") {
+		t.Errorf("Referrer = %v, want the beginning of the referrer", event.Referrer)
+	}
+}
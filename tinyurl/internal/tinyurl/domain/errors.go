@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"fmt"
+
 	tinyError "github.com/christapa/tinyurl/pkg/error"
 )
 
@@ -15,3 +17,27 @@ func NewInternalError(message string) error {
 func NewNotFoundError() error {
 	return tinyError.New(tinyError.NotFound, "URL not found")
 }
+
+// NewDisabledError : The url exists but was disabled by its owner
+func NewDisabledError() error {
+	return tinyError.New(tinyError.Gone, "URL is disabled")
+}
+
+// NewNotOwnerError : The url was created by another user
+func NewNotOwnerError() error {
+	return tinyError.New(tinyError.PermissionDenied, "URL belongs to another user")
+}
+
+// NewExpirationConflictError : The original URL is already shortened with another expiration date
+func NewExpirationConflictError() error {
+	return tinyError.New(tinyError.AlreadyExists, "URL already shortened with a different expiration date")
+}
+
+// NewSlugCollisionError : No free shorten URL was found for a new original URL
+func NewSlugCollisionError() error {
+	return tinyError.New(tinyError.Internal, "could not generate a unique shorten URL")
+}
+
+func NewAliasTakenError(alias string) error {
+	return tinyError.New(tinyError.AlreadyExists, fmt.Sprintf("alias %q is already taken", alias))
+}
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// ExpirationPolicy : What to do when an original URL is shortened again with another expiration date
+type ExpirationPolicy string
+
+const (
+	// ExpirationPolicyReject refuses the request
+	ExpirationPolicyReject ExpirationPolicy = "reject"
+	// ExpirationPolicyExtend keeps the existing shorten URL and pushes its expiration date back
+	ExpirationPolicyExtend ExpirationPolicy = "extend"
+	// ExpirationPolicyNew creates another shorten URL with the requested expiration date
+	ExpirationPolicyNew ExpirationPolicy = "new"
+)
+
+func ParseExpirationPolicy(policy string) (ExpirationPolicy, error) {
+	switch ExpirationPolicy(policy) {
+	case ExpirationPolicyReject, ExpirationPolicyExtend, ExpirationPolicyNew:
+		return ExpirationPolicy(policy), nil
+	default:
+		return "", fmt.Errorf("unknown expiration policy %q", policy)
+	}
+}
+
+// HasSameExpiration : A zero expiration means the url never expires
+func (u *Url) HasSameExpiration(expiration time.Time) bool {
+	return u.Expiration.Equal(expiration)
+}
+
+// ExpiresBefore : Whether the url expires before the given expiration date
+func (u *Url) ExpiresBefore(expiration time.Time) bool {
+	if u.Expiration.IsZero() {
+		return false
+	}
+
+	return expiration.IsZero() || u.Expiration.Before(expiration)
+}
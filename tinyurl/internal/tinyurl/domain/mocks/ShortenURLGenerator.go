@@ -2,29 +2,43 @@
 
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
 
 // ShortenURLGenerator is an autogenerated mock type for the ShortenURLGenerator type
 type ShortenURLGenerator struct {
 	mock.Mock
 }
 
-// GenerateShortenURL provides a mock function with given fields: url
-func (_m *ShortenURLGenerator) GenerateShortenURL(url string) string {
-	ret := _m.Called(url)
+// GenerateShortenURL provides a mock function with given fields: ctx, url
+func (_m *ShortenURLGenerator) GenerateShortenURL(ctx context.Context, url string) (string, error) {
+	ret := _m.Called(ctx, url)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GenerateShortenURL")
 	}
 
 	var r0 string
-	if rf, ok := ret.Get(0).(func(string) string); ok {
-		r0 = rf(url)
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
+		return rf(ctx, url)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = rf(ctx, url)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, url)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // NewShortenURLGenerator creates a new instance of ShortenURLGenerator. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
@@ -7,6 +7,8 @@ import (
 
 	domain "github.com/christapa/tinyurl/internal/tinyurl/domain"
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // UrlRepository is an autogenerated mock type for the UrlRepository type
@@ -14,6 +16,62 @@ type UrlRepository struct {
 	mock.Mock
 }
 
+// ArchiveExpiredUrls provides a mock function with given fields: ctx, before, limit
+func (_m *UrlRepository) ArchiveExpiredUrls(ctx context.Context, before time.Time, limit int) (int, error) {
+	ret := _m.Called(ctx, before, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ArchiveExpiredUrls")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) (int, error)); ok {
+		return rf(ctx, before, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) int); ok {
+		r0 = rf(ctx, before, limit)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, int) error); ok {
+		r1 = rf(ctx, before, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteExpiredUrls provides a mock function with given fields: ctx, before, limit
+func (_m *UrlRepository) DeleteExpiredUrls(ctx context.Context, before time.Time, limit int) (int, error) {
+	ret := _m.Called(ctx, before, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteExpiredUrls")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) (int, error)); ok {
+		return rf(ctx, before, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) int); ok {
+		r0 = rf(ctx, before, limit)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, int) error); ok {
+		r1 = rf(ctx, before, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DeleteUrl provides a mock function with given fields: ctx, shortUrl
 func (_m *UrlRepository) DeleteUrl(ctx context.Context, shortUrl string) error {
 	ret := _m.Called(ctx, shortUrl)
@@ -60,6 +118,34 @@ func (_m *UrlRepository) GetUrl(ctx context.Context, shortUrl string) (domain.Ur
 	return r0, r1
 }
 
+// GetUrlByOriginalURL provides a mock function with given fields: ctx, owner, originalURL, expiration
+func (_m *UrlRepository) GetUrlByOriginalURL(ctx context.Context, owner string, originalURL string, expiration time.Time) (domain.Url, error) {
+	ret := _m.Called(ctx, owner, originalURL, expiration)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUrlByOriginalURL")
+	}
+
+	var r0 domain.Url
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) (domain.Url, error)); ok {
+		return rf(ctx, owner, originalURL, expiration)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) domain.Url); ok {
+		r0 = rf(ctx, owner, originalURL, expiration)
+	} else {
+		r0 = ret.Get(0).(domain.Url)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, time.Time) error); ok {
+		r1 = rf(ctx, owner, originalURL, expiration)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // IncrementCounter provides a mock function with given fields: ctx, shortUrl
 func (_m *UrlRepository) IncrementCounter(ctx context.Context, shortUrl string) error {
 	ret := _m.Called(ctx, shortUrl)
@@ -78,6 +164,54 @@ func (_m *UrlRepository) IncrementCounter(ctx context.Context, shortUrl string)
 	return r0
 }
 
+// IncrementCounters provides a mock function with given fields: ctx, increments
+func (_m *UrlRepository) IncrementCounters(ctx context.Context, increments map[string]int) error {
+	ret := _m.Called(ctx, increments)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IncrementCounters")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, map[string]int) error); ok {
+		r0 = rf(ctx, increments)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ListUrlsByOwner provides a mock function with given fields: ctx, owner
+func (_m *UrlRepository) ListUrlsByOwner(ctx context.Context, owner string) ([]domain.Url, error) {
+	ret := _m.Called(ctx, owner)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListUrlsByOwner")
+	}
+
+	var r0 []domain.Url
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]domain.Url, error)); ok {
+		return rf(ctx, owner)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []domain.Url); ok {
+		r0 = rf(ctx, owner)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Url)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, owner)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // StoreUrl provides a mock function with given fields: ctx, url
 func (_m *UrlRepository) StoreUrl(ctx context.Context, url domain.Url) (domain.Url, error) {
 	ret := _m.Called(ctx, url)
@@ -106,6 +240,34 @@ func (_m *UrlRepository) StoreUrl(ctx context.Context, url domain.Url) (domain.U
 	return r0, r1
 }
 
+// UpdateUrl provides a mock function with given fields: ctx, url
+func (_m *UrlRepository) UpdateUrl(ctx context.Context, url domain.Url) (domain.Url, error) {
+	ret := _m.Called(ctx, url)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateUrl")
+	}
+
+	var r0 domain.Url
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Url) (domain.Url, error)); ok {
+		return rf(ctx, url)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Url) domain.Url); ok {
+		r0 = rf(ctx, url)
+	} else {
+		r0 = ret.Get(0).(domain.Url)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Url) error); ok {
+		r1 = rf(ctx, url)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewUrlRepository creates a new instance of UrlRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewUrlRepository(t interface {
@@ -1,10 +1,29 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type UrlRepository interface {
 	StoreUrl(ctx context.Context, url Url) (Url, error)
 	GetUrl(ctx context.Context, shortUrl string) (Url, error)
+	// GetUrlByOriginalURL returns a non expired url of the owner shortening originalURL
+	// An enabled url with the given expiration is returned first
+	GetUrlByOriginalURL(ctx context.Context, owner string, originalURL string, expiration time.Time) (Url, error)
+	ListUrlsByOwner(ctx context.Context, owner string) ([]Url, error)
+	UpdateUrl(ctx context.Context, url Url) (Url, error)
 	IncrementCounter(ctx context.Context, shortUrl string) error
+	// IncrementCounters adds each increment to the counter of its shorten URL, unknown ones are ignored
+	IncrementCounters(ctx context.Context, increments map[string]int) error
 	DeleteUrl(ctx context.Context, shortUrl string) error
+	// DeleteExpiredUrls deletes at most limit urls expired before the date and returns how many were deleted
+	DeleteExpiredUrls(ctx context.Context, before time.Time, limit int) (int, error)
+	// ArchiveExpiredUrls is DeleteExpiredUrls keeping a copy of the deleted urls
+	ArchiveExpiredUrls(ctx context.Context, before time.Time, limit int) (int, error)
+}
+
+// UserRepository : Users are stored by the postgresql/user module
+type UserRepository interface {
+	IsPasswordMatch(ctx context.Context, email string, password string) (bool, error)
 }
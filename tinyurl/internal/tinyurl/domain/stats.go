@@ -0,0 +1,187 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// StatsBucket : Granularity of the clicks time series
+type StatsBucket string
+
+const (
+	StatsBucketHour  StatsBucket = "hour"
+	StatsBucketDay   StatsBucket = "day"
+	StatsBucketMonth StatsBucket = "month"
+)
+
+// Maximum number of buckets returned for one request
+const maxStatsBuckets = 24 * 31
+
+// DirectReferrer : Referrer reported for clicks without a Referer header
+const DirectReferrer = "(direct)"
+
+// ClickDimension : Attribute of the click events used to group them
+type ClickDimension string
+
+const (
+	ClickDimensionReferrer  ClickDimension = "referrer"
+	ClickDimensionUserAgent ClickDimension = "user_agent"
+	ClickDimensionLanguage  ClickDimension = "accept_language"
+)
+
+type ClickCount struct {
+	Start time.Time
+	Count int
+}
+
+type Breakdown struct {
+	Value string
+	Count int
+}
+
+// ClickStats : Analytics of a shorten URL over [From, To)
+type ClickStats struct {
+	Url       Url
+	From      time.Time
+	To        time.Time
+	Bucket    StatsBucket
+	Clicks    []ClickCount
+	Referrers []Breakdown
+	Browsers  []Breakdown
+	Devices   []Breakdown
+	Languages []Breakdown
+}
+
+type ClickStatsRepository interface {
+	// CountClicks returns the non empty buckets, ordered by start
+	CountClicks(ctx context.Context, shortenURL string, from time.Time, to time.Time, bucket StatsBucket) ([]ClickCount, error)
+	// CountClicksBy returns the number of clicks per value of the dimension, most clicked first, at most limit values
+	CountClicksBy(ctx context.Context, shortenURL string, from time.Time, to time.Time, dimension ClickDimension, limit int) ([]Breakdown, error)
+}
+
+func ParseStatsBucket(bucket string) (StatsBucket, error) {
+	switch StatsBucket(bucket) {
+	case StatsBucketHour, StatsBucketDay, StatsBucketMonth:
+		return StatsBucket(bucket), nil
+	default:
+		return "", NewInvalidInputError(fmt.Sprintf("unknown bucket %q", bucket))
+	}
+}
+
+// ValidateStatsRange : The range must be ordered and not give too many buckets
+func ValidateStatsRange(from time.Time, to time.Time, bucket StatsBucket) error {
+	if !from.Before(to) {
+		return NewInvalidInputError("from must be before to")
+	}
+
+	if len(bucketStarts(from, to, bucket)) > maxStatsBuckets {
+		return NewInvalidInputError(fmt.Sprintf("range is too large for %s buckets", bucket))
+	}
+
+	return nil
+}
+
+// TruncateToBucket : Start of the bucket containing t, in UTC
+func TruncateToBucket(t time.Time, bucket StatsBucket) time.Time {
+	t = t.UTC()
+
+	switch bucket {
+	case StatsBucketHour:
+		return t.Truncate(time.Hour)
+	case StatsBucketMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+}
+
+// FillClickCounts : Add the empty buckets of the range so the time series has no gap
+func FillClickCounts(counts []ClickCount, from time.Time, to time.Time, bucket StatsBucket) []ClickCount {
+	countByStart := make(map[time.Time]int, len(counts))
+	for _, count := range counts {
+		countByStart[TruncateToBucket(count.Start, bucket)] += count.Count
+	}
+
+	starts := bucketStarts(from, to, bucket)
+	filled := make([]ClickCount, 0, len(starts))
+	for _, start := range starts {
+		filled = append(filled, ClickCount{Start: start, Count: countByStart[start]})
+	}
+
+	return filled
+}
+
+func bucketStarts(from time.Time, to time.Time, bucket StatsBucket) []time.Time {
+	var starts []time.Time
+	for start := TruncateToBucket(from, bucket); start.Before(to); start = nextBucket(start, bucket) {
+		starts = append(starts, start)
+		if len(starts) > maxStatsBuckets {
+			break
+		}
+	}
+
+	return starts
+}
+
+func nextBucket(start time.Time, bucket StatsBucket) time.Time {
+	switch bucket {
+	case StatsBucketHour:
+		return start.Add(time.Hour)
+	case StatsBucketMonth:
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// RegroupBreakdown : Merge the values mapped to the same group, most clicked first, at most limit groups
+func RegroupBreakdown(breakdown []Breakdown, group func(string) string, limit int) []Breakdown {
+	countByGroup := make(map[string]int)
+	for _, entry := range breakdown {
+		countByGroup[group(entry.Value)] += entry.Count
+	}
+
+	regrouped := make([]Breakdown, 0, len(countByGroup))
+	for value, count := range countByGroup {
+		regrouped = append(regrouped, Breakdown{Value: value, Count: count})
+	}
+
+	slices.SortFunc(regrouped, func(a, b Breakdown) int {
+		if a.Count != b.Count {
+			return b.Count - a.Count
+		}
+		return strings.Compare(a.Value, b.Value)
+	})
+
+	if len(regrouped) > limit {
+		regrouped = regrouped[:limit]
+	}
+
+	return regrouped
+}
+
+// ReferrerGroup : Empty referrers are direct visits
+func ReferrerGroup(referrer string) string {
+	if referrer == "" {
+		return DirectReferrer
+	}
+
+	return referrer
+}
+
+// PrimaryLanguage : First language of an Accept-Language header, without region
+// "fr-FR,fr;q=0.9,en;q=0.8" gives "fr"
+func PrimaryLanguage(acceptLanguage string) string {
+	first, _, _ := strings.Cut(acceptLanguage, ",")
+	first, _, _ = strings.Cut(first, ";")
+	primary, _, _ := strings.Cut(strings.TrimSpace(first), "-")
+
+	if primary == "" || primary == "*" {
+		return "unknown"
+	}
+
+	return strings.ToLower(primary)
+}
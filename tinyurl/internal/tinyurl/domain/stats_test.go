@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTruncateToBucket(t *testing.T) {
+	clickedAt := time.Date(2024, 3, 15, 13, 45, 10, 0, time.UTC)
+
+	tests := []struct {
+		bucket StatsBucket
+		want   time.Time
+	}{
+		{StatsBucketHour, time.Date(2024, 3, 15, 13, 0, 0, 0, time.UTC)},
+		{StatsBucketDay, time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{StatsBucketMonth, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		if got := TruncateToBucket(clickedAt, tt.bucket); !got.Equal(tt.want) {
+			t.Errorf("TruncateToBucket(%v) = %v, want %v", tt.bucket, got, tt.want)
+		}
+	}
+}
+
+func TestFillClickCounts(t *testing.T) {
+	from := time.Date(2024, 1, 20, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	got := FillClickCounts([]ClickCount{{Start: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), Count: 7}}, from, to, StatsBucketMonth)
+	want := []ClickCount{
+		{Start: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Count: 0},
+		{Start: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), Count: 7},
+		{Start: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), Count: 0},
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("FillClickCounts() = %v, want %v", got, want)
+	}
+
+	for i := range want {
+		if !got[i].Start.Equal(want[i].Start) || got[i].Count != want[i].Count {
+			t.Errorf("FillClickCounts()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestUserAgentClassification(t *testing.T) {
+	tests := []struct {
+		userAgent string
+		browser   string
+		device    string
+	}{
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36 Edg/124.0.0.0", "Edge", DeviceDesktop},
+		{"Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Mobile Safari/537.36", "Chrome", DeviceMobile},
+		{"Mozilla/5.0 (iPad; CPU OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Mobile/15E148 Safari/604.1", "Safari", DeviceTablet},
+		{"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", "Bot", DeviceBot},
+		{"", "Unknown", DeviceUnknown},
+	}
+
+	for _, tt := range tests {
+		if got := BrowserFamily(tt.userAgent); got != tt.browser {
+			t.Errorf("BrowserFamily(%q) = %v, want %v", tt.userAgent, got, tt.browser)
+		}
+
+		if got := DeviceType(tt.userAgent); got != tt.device {
+			t.Errorf("DeviceType(%q) = %v, want %v", tt.userAgent, got, tt.device)
+		}
+	}
+}
+
+func TestPrimaryLanguage(t *testing.T) {
+	tests := map[string]string{
+		"fr-FR,fr;q=0.9,en;q=0.8": "fr",
+		"en-US":                   "en",
+		"EN;q=0.5":                "en",
+		"*":                       "unknown",
+		"":                        "unknown",
+	}
+
+	for acceptLanguage, want := range tests {
+		if got := PrimaryLanguage(acceptLanguage); got != want {
+			t.Errorf("PrimaryLanguage(%q) = %v, want %v", acceptLanguage, got, want)
+		}
+	}
+}
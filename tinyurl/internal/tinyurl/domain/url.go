@@ -9,21 +9,28 @@ type Url struct {
 	OriginalURL string
 	Counter     int
 	Expiration  time.Time
+	// Disabled urls are kept but do not redirect anymore
+	Disabled bool
+	// Owner is the email of the user who created the url, empty for urls created before ownership
+	Owner string
 }
 
-func NewURL(originalURL string, expiration time.Time) (Url, error) {
-	if err := isValidOriginalURL(originalURL); err != nil {
-		return Url{}, NewInvalidInputError(err.Error())
+// NewURL : The original URL is checked by the validator
+func NewURL(validator URLValidator, shortenURL string, originalURL string, expiration time.Time) (Url, error) {
+	if shortenURL == "" {
+		return Url{}, NewInvalidInputError("shorten URL is empty")
+	}
+
+	if err := validator.Validate(originalURL); err != nil {
+		return Url{}, err
 	}
 
 	if !expiration.IsZero() && expiration.Before(time.Now()) {
 		return Url{}, NewInvalidInputError("expiration date is in the past")
 	}
 
-	shortenURL := ShortenURLGeneratorInstance.GenerateShortenURL(originalURL)
-
 	return Url{
-		ShortenURL:  string(shortenURL[:]),
+		ShortenURL:  shortenURL,
 		OriginalURL: originalURL,
 		Counter:     0,
 		Expiration:  expiration,
@@ -34,7 +41,59 @@ func (u *Url) IncrementCounter() {
 	u.Counter++
 }
 
+// IsOwnedBy : Urls without owner belong to nobody
+func (u *Url) IsOwnedBy(owner string) bool {
+	return u.Owner != "" && u.Owner == owner
+}
+
 func (u *Url) IsExpired() bool {
 
 	return !u.Expiration.IsZero() && u.Expiration.Before(time.Now())
 }
+
+// UrlUpdate : Changes of an existing url, nil fields are left untouched
+type UrlUpdate struct {
+	OriginalURL *string
+	// Expiration is removed when set to the zero time
+	Expiration *time.Time
+}
+
+// Apply : Change the url, nothing is changed when the update is invalid
+func (u *Url) Apply(validator URLValidator, update UrlUpdate) error {
+	updated := *u
+
+	if update.OriginalURL != nil {
+		if err := updated.ChangeDestination(validator, *update.OriginalURL); err != nil {
+			return err
+		}
+	}
+
+	if update.Expiration != nil {
+		if err := updated.ChangeExpiration(*update.Expiration); err != nil {
+			return err
+		}
+	}
+
+	*u = updated
+	return nil
+}
+
+// ChangeDestination : Point the url to another original URL
+func (u *Url) ChangeDestination(validator URLValidator, originalURL string) error {
+	if err := validator.Validate(originalURL); err != nil {
+		return err
+	}
+
+	u.OriginalURL = originalURL
+	return nil
+}
+
+// ChangeExpiration : A zero expiration means the url never expires
+func (u *Url) ChangeExpiration(expiration time.Time) error {
+	if !expiration.IsZero() && expiration.Before(time.Now()) {
+		return NewInvalidInputError("expiration date is in the past")
+	}
+
+	u.Expiration = expiration
+	return nil
+}
@@ -1,30 +1,62 @@
 package domain
 
 import (
+	"context"
+	"crypto/rand"
 	"crypto/sha256"
 	"encoding/base64"
+	"fmt"
 )
 
-var ShortenURLGeneratorInstance ShortenURLGenerator = defaultShortenURLGenerator{}
+// Base62Alphabet : Default alphabet of the sequence, random and obfuscated generators
+const Base62Alphabet = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
 
-func SetShortenURLGenerator(generator ShortenURLGenerator) {
-	ShortenURLGeneratorInstance = generator
+// ShortenURLGenerator : Strategy used by the service to pick the shorten URL of an original URL
+type ShortenURLGenerator interface {
+	GenerateShortenURL(ctx context.Context, url string) (string, error)
+}
+
+// Sequence : Monotonic source of identifiers, shared by every instance of the service
+type Sequence interface {
+	NextValue(ctx context.Context) (uint64, error)
+}
+
+// SaltURL : Input given to the generator when the previous shorten URL collided
+// The owner and a random nonce give another input on every call, so the same original URL
+// shortened again and again does not run out of salted shorten URLs
+// The NUL separator can not appear in a valid original URL
+func SaltURL(url string, owner string) (string, error) {
+	nonce := make([]byte, 12)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	return url + "\x00" + owner + "\x00" + base64.RawURLEncoding.EncodeToString(nonce), nil
+}
+
+// hashShortenURLGenerator : Prefix of the base64 SHA-256 of the original URL
+// The same original URL always gives the same shorten URL
+type hashShortenURLGenerator struct {
+	length int
 }
 
-type defaultShortenURLGenerator struct{}
+func NewHashShortenURLGenerator(length int) (ShortenURLGenerator, error) {
+	maxLength := base64.URLEncoding.EncodedLen(sha256.Size) - 1
+	if length < 1 || length > maxLength {
+		return nil, fmt.Errorf("hash shorten URL length must be between 1 and %d", maxLength)
+	}
+
+	return hashShortenURLGenerator{length: length}, nil
+}
 
-func (d defaultShortenURLGenerator) GenerateShortenURL(url string) string {
+func (h hashShortenURLGenerator) GenerateShortenURL(_ context.Context, url string) (string, error) {
 	hash := sha256.New()
 	hash.Write([]byte(url))
 	hashBytes := hash.Sum(nil)
 
 	base64Hash := base64.URLEncoding.EncodeToString(hashBytes)
 
-	shortenedURL := base64Hash[:8]
+	shortenedURL := base64Hash[:h.length]
 
-	return shortenedURL
-}
-
-type ShortenURLGenerator interface {
-	GenerateShortenURL(url string) string
+	return shortenedURL, nil
 }
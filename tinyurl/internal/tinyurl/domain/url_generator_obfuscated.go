@@ -0,0 +1,118 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// ObfuscatedShortenURLGenerator : Sqids-style encoding of the next value of a sequence
+// Consecutive values give unrelated looking shorten URLs, and Decode gives the value back
+type ObfuscatedShortenURLGenerator struct {
+	sequence  Sequence
+	alphabet  []rune
+	minLength int
+}
+
+// NewObfuscatedShortenURLGenerator : The alphabet is shuffled once, so two deployments
+// sharing a sequence but not an alphabet produce different shorten URLs
+func NewObfuscatedShortenURLGenerator(sequence Sequence, alphabet string, minLength int) (*ObfuscatedShortenURLGenerator, error) {
+	runes, err := parseAlphabet(alphabet)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(runes) < 3 {
+		return nil, fmt.Errorf("obfuscated alphabet must contain at least 3 characters")
+	}
+
+	if minLength < 0 {
+		return nil, fmt.Errorf("obfuscated shorten URL length must not be negative")
+	}
+
+	return &ObfuscatedShortenURLGenerator{
+		sequence:  sequence,
+		alphabet:  shuffleAlphabet(runes),
+		minLength: minLength,
+	}, nil
+}
+
+func (o *ObfuscatedShortenURLGenerator) GenerateShortenURL(ctx context.Context, _ string) (string, error) {
+	value, err := o.sequence.NextValue(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return o.Encode(value), nil
+}
+
+// Encode : The first character selects a rotation of the alphabet used for the value,
+// padding after the separator brings the shorten URL to the minimum length
+func (o *ObfuscatedShortenURLGenerator) Encode(value uint64) string {
+	offset := (uint64(o.alphabet[value%uint64(len(o.alphabet))]) + 1) % uint64(len(o.alphabet))
+
+	alphabet := rotateAlphabet(o.alphabet, int(offset))
+	prefix := alphabet[0]
+	slices.Reverse(alphabet)
+
+	encoded := append([]rune{prefix}, encodeNumber(value, alphabet[1:])...)
+
+	if len(encoded) < o.minLength {
+		encoded = append(encoded, alphabet[0])
+
+		for len(encoded) < o.minLength {
+			alphabet = shuffleAlphabet(alphabet)
+			encoded = append(encoded, alphabet[:min(o.minLength-len(encoded), len(alphabet))]...)
+		}
+	}
+
+	return string(encoded)
+}
+
+// Decode : Value encoded in a shorten URL produced by Encode
+func (o *ObfuscatedShortenURLGenerator) Decode(shortenURL string) (uint64, error) {
+	encoded := []rune(shortenURL)
+	if len(encoded) < 2 {
+		return 0, fmt.Errorf("shorten URL %q is too short", shortenURL)
+	}
+
+	offset := slices.Index(o.alphabet, encoded[0])
+	if offset < 0 {
+		return 0, fmt.Errorf("%q is not part of the alphabet", encoded[0])
+	}
+
+	alphabet := rotateAlphabet(o.alphabet, offset)
+	slices.Reverse(alphabet)
+
+	if separator := slices.Index(encoded, alphabet[0]); separator >= 0 {
+		encoded = encoded[:separator]
+	}
+
+	value, err := decodeNumber(encoded[1:], alphabet[1:])
+	if err != nil {
+		return 0, err
+	}
+
+	// Reject shorten URLs that decode to a value but would not be produced by Encode
+	if o.Encode(value) != shortenURL {
+		return 0, fmt.Errorf("shorten URL %q is not canonical", shortenURL)
+	}
+
+	return value, nil
+}
+
+func rotateAlphabet(alphabet []rune, offset int) []rune {
+	return append(slices.Clone(alphabet[offset:]), alphabet[:offset]...)
+}
+
+// shuffleAlphabet : Deterministic shuffle, the same alphabet always gives the same result
+func shuffleAlphabet(alphabet []rune) []rune {
+	shuffled := slices.Clone(alphabet)
+
+	for i, j := 0, len(shuffled)-1; j > 0; i, j = i+1, j-1 {
+		r := (i*j + int(shuffled[i]) + int(shuffled[j])) % len(shuffled)
+		shuffled[i], shuffled[r] = shuffled[r], shuffled[i]
+	}
+
+	return shuffled
+}
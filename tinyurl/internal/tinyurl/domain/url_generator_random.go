@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// randomShortenURLGenerator : Uniformly random shorten URL drawn with crypto/rand
+// The original URL is ignored, collisions are handled by the service retries
+type randomShortenURLGenerator struct {
+	alphabet []rune
+	length   int
+}
+
+func NewRandomShortenURLGenerator(alphabet string, length int) (ShortenURLGenerator, error) {
+	runes, err := parseAlphabet(alphabet)
+	if err != nil {
+		return nil, err
+	}
+
+	if length < 1 {
+		return nil, fmt.Errorf("random shorten URL length must be positive")
+	}
+
+	return randomShortenURLGenerator{alphabet: runes, length: length}, nil
+}
+
+func (r randomShortenURLGenerator) GenerateShortenURL(_ context.Context, _ string) (string, error) {
+	alphabetLength := big.NewInt(int64(len(r.alphabet)))
+
+	shortenURL := make([]rune, r.length)
+	for i := range shortenURL {
+		index, err := rand.Int(rand.Reader, alphabetLength)
+		if err != nil {
+			return "", NewInternalError(err.Error())
+		}
+
+		shortenURL[i] = r.alphabet[index.Int64()]
+	}
+
+	return string(shortenURL), nil
+}
+
+// parseAlphabet : Alphabets need at least two unique characters
+// Only the unreserved URL characters but . are accepted, others such as / ? # or % break the routing of the slugs
+func parseAlphabet(alphabet string) ([]rune, error) {
+	if alphabet == "" {
+		alphabet = Base62Alphabet
+	}
+
+	runes := []rune(alphabet)
+	if len(runes) < 2 {
+		return nil, fmt.Errorf("alphabet must contain at least 2 characters")
+	}
+
+	seen := make(map[rune]bool, len(runes))
+	for _, r := range runes {
+		if !isSlugRune(r) {
+			return nil, fmt.Errorf("alphabet contains %q, only A-Z a-z 0-9 - _ ~ are allowed", r)
+		}
+		if seen[r] {
+			return nil, fmt.Errorf("alphabet contains %q more than once", r)
+		}
+		seen[r] = true
+	}
+
+	return runes, nil
+}
+
+// isSlugRune : Unreserved characters of RFC 3986, never escaped in a path
+// Without . as the slugs . and .. are dot segments, removed from the path by clients
+func isSlugRune(r rune) bool {
+	return r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || strings.ContainsRune("-_~", r)
+}
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// sequenceShortenURLGenerator : Base62 encoding of the next value of a sequence
+// Shorten URLs never collide but are predictable
+type sequenceShortenURLGenerator struct {
+	sequence  Sequence
+	alphabet  []rune
+	minLength int
+}
+
+func NewSequenceShortenURLGenerator(sequence Sequence, minLength int) (ShortenURLGenerator, error) {
+	if minLength < 0 {
+		return nil, fmt.Errorf("sequence shorten URL length must not be negative")
+	}
+
+	return sequenceShortenURLGenerator{
+		sequence:  sequence,
+		alphabet:  []rune(Base62Alphabet),
+		minLength: minLength,
+	}, nil
+}
+
+func (s sequenceShortenURLGenerator) GenerateShortenURL(ctx context.Context, _ string) (string, error) {
+	value, err := s.sequence.NextValue(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	shortenURL := encodeNumber(value, s.alphabet)
+	for len(shortenURL) < s.minLength {
+		shortenURL = append([]rune{s.alphabet[0]}, shortenURL...)
+	}
+
+	return string(shortenURL), nil
+}
+
+func encodeNumber(number uint64, alphabet []rune) []rune {
+	base := uint64(len(alphabet))
+
+	var encoded []rune
+	for {
+		encoded = append([]rune{alphabet[number%base]}, encoded...)
+		number /= base
+		if number == 0 {
+			return encoded
+		}
+	}
+}
+
+func decodeNumber(encoded []rune, alphabet []rune) (uint64, error) {
+	base := uint64(len(alphabet))
+
+	var number uint64
+	for _, r := range encoded {
+		index := slices.Index(alphabet, r)
+		if index < 0 {
+			return 0, fmt.Errorf("%q is not part of the alphabet", r)
+		}
+
+		if number > (^uint64(0)-uint64(index))/base {
+			return 0, fmt.Errorf("encoded number overflows")
+		}
+		number = number*base + uint64(index)
+	}
+
+	return number, nil
+}
@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+type MockSequence struct {
+	value uint64
+}
+
+func (m *MockSequence) NextValue(ctx context.Context) (uint64, error) {
+	m.value++
+	return m.value, nil
+}
+
+func TestHashShortenURLGenerator(t *testing.T) {
+	generator, err := NewHashShortenURLGenerator(8)
+	if err != nil {
+		t.Fatalf("NewHashShortenURLGenerator() error = %v", err)
+	}
+
+	url := "https://www.google.com"
+	want := "rGu2aeQO"
+	got, err := generator.GenerateShortenURL(context.Background(), url)
+	if err != nil || got != want {
+		t.Errorf("GenerateShortenURL() = %v, %v, want %v", got, err, want)
+	}
+
+	salt, err := SaltURL(url, "test@test.com")
+	if err != nil {
+		t.Fatalf("SaltURL() error = %v", err)
+	}
+	salted, _ := generator.GenerateShortenURL(context.Background(), salt)
+	if salted == want {
+		t.Errorf("GenerateShortenURL() with salt = %v, want a different shorten URL", salted)
+	}
+
+	otherSalt, _ := SaltURL(url, "test@test.com")
+	if otherSalt == salt {
+		t.Errorf("SaltURL() = %v twice, want another salt on every call", salt)
+	}
+}
+
+func TestRandomShortenURLGenerator(t *testing.T) {
+	generator, err := NewRandomShortenURLGenerator("abc", 12)
+	if err != nil {
+		t.Fatalf("NewRandomShortenURLGenerator() error = %v", err)
+	}
+
+	got, err := generator.GenerateShortenURL(context.Background(), "https://www.google.com")
+	if err != nil {
+		t.Fatalf("GenerateShortenURL() error = %v", err)
+	}
+
+	if len(got) != 12 || strings.Trim(got, "abc") != "" {
+		t.Errorf("GenerateShortenURL() = %v, want 12 characters of the alphabet", got)
+	}
+
+	if _, err := NewRandomShortenURLGenerator("aa", 12); err == nil {
+		t.Errorf("NewRandomShortenURLGenerator() with duplicated characters should fail")
+	}
+
+	if _, err := NewRandomShortenURLGenerator("abc-_~", 12); err != nil {
+		t.Errorf("NewRandomShortenURLGenerator() with unreserved characters error = %v", err)
+	}
+
+	for _, alphabet := range []string{"abc/", "abc?", "abc#", "abc%", "abc é", "abc."} {
+		if _, err := NewRandomShortenURLGenerator(alphabet, 12); err == nil {
+			t.Errorf("NewRandomShortenURLGenerator(%q) should fail", alphabet)
+		}
+	}
+}
+
+func TestSequenceShortenURLGenerator(t *testing.T) {
+	generator, err := NewSequenceShortenURLGenerator(&MockSequence{value: 61}, 3)
+	if err != nil {
+		t.Fatalf("NewSequenceShortenURLGenerator() error = %v", err)
+	}
+
+	for _, want := range []string{"010", "011"} {
+		got, err := generator.GenerateShortenURL(context.Background(), "https://www.google.com")
+		if err != nil || got != want {
+			t.Errorf("GenerateShortenURL() = %v, %v, want %v", got, err, want)
+		}
+	}
+}
+
+func TestObfuscatedShortenURLGenerator(t *testing.T) {
+	generator, err := NewObfuscatedShortenURLGenerator(&MockSequence{}, "", 6)
+	if err != nil {
+		t.Fatalf("NewObfuscatedShortenURLGenerator() error = %v", err)
+	}
+
+	seen := make(map[string]bool)
+	for value := uint64(1); value <= 1000; value++ {
+		got, err := generator.GenerateShortenURL(context.Background(), "https://www.google.com")
+		if err != nil {
+			t.Fatalf("GenerateShortenURL() error = %v", err)
+		}
+
+		if len(got) < 6 {
+			t.Errorf("GenerateShortenURL() = %v, want at least 6 characters", got)
+		}
+
+		if seen[got] {
+			t.Errorf("GenerateShortenURL() = %v, generated twice", got)
+		}
+		seen[got] = true
+
+		decoded, err := generator.Decode(got)
+		if err != nil || decoded != value {
+			t.Errorf("Decode(%v) = %v, %v, want %v", got, decoded, err, value)
+		}
+	}
+
+	if _, err := generator.Decode("!!"); err == nil {
+		t.Errorf("Decode() of characters outside the alphabet should fail")
+	}
+}
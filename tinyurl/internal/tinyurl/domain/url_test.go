@@ -7,14 +7,9 @@ import (
 	"time"
 )
 
-type MockShortenURLGenerator struct{}
-
-func (m MockShortenURLGenerator) GenerateShortenURL(url string) string {
-	return url
-}
-
 func TestNewURL(t *testing.T) {
 	type args struct {
+		shortenURL  string
 		originalURL string
 		expiration  time.Time
 	}
@@ -27,10 +22,11 @@ func TestNewURL(t *testing.T) {
 		{
 			name: "Test NewURL",
 			args: args{
+				shortenURL:  "rGu2aeQO",
 				originalURL: "https://www.google.com",
 			},
 			want: Url{
-				ShortenURL:  "https://www.google.com",
+				ShortenURL:  "rGu2aeQO",
 				OriginalURL: "https://www.google.com",
 				Counter:     0,
 			},
@@ -39,19 +35,26 @@ func TestNewURL(t *testing.T) {
 		{
 			name: "Expired URL",
 			args: args{
+				shortenURL:  "rGu2aeQO",
 				originalURL: "https://www.google.com",
 				expiration:  time.Now().Add(-time.Hour),
 			},
 			want: Url{},
 			err:  NewInvalidInputError("expiration date is in the past"),
 		},
+		{
+			name: "Empty shorten URL",
+			args: args{
+				originalURL: "https://www.google.com",
+			},
+			want: Url{},
+			err:  NewInvalidInputError("shorten URL is empty"),
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			SetShortenURLGenerator(MockShortenURLGenerator{})
-
-			got, err := NewURL(tt.args.originalURL, tt.args.expiration)
+			got, err := NewURL(NewURLValidator(DefaultValidationRules()), tt.args.shortenURL, tt.args.originalURL, tt.args.expiration)
 			if err != nil && !errors.Is(err, tt.err) {
 				t.Errorf("NewURL() = %v, want %v", err, tt.err)
 			}
@@ -63,15 +66,6 @@ func TestNewURL(t *testing.T) {
 	}
 }
 
-func TestDefaultShortenURLGenerator(t *testing.T) {
-	url := "https://www.google.com"
-	want := "rGu2aeQO"
-	got := defaultShortenURLGenerator{}.GenerateShortenURL(url)
-	if got != want {
-		t.Errorf("GenerateShortenURL() = %v, want %v", got, want)
-	}
-}
-
 func TestUrl_IsExpired(t *testing.T) {
 	type fields struct {
 		ShortenURL  string
@@ -155,3 +149,60 @@ func TestUrl_IncrementCounter(t *testing.T) {
 		})
 	}
 }
+
+func TestUrl_Apply(t *testing.T) {
+	destination := "https://www.bing.com"
+	invalidDestination := "javascript:alert(1)"
+	expiration := time.Now().Add(time.Hour).Truncate(time.Second)
+	noExpiration := time.Time{}
+	pastExpiration := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name   string
+		update UrlUpdate
+		want   Url
+		err    error
+	}{
+		{
+			name:   "Change destination",
+			update: UrlUpdate{OriginalURL: &destination},
+			want:   Url{ShortenURL: "rGu2aeQO", OriginalURL: destination, Expiration: expiration},
+		},
+		{
+			name:   "Remove expiration",
+			update: UrlUpdate{Expiration: &noExpiration},
+			want:   Url{ShortenURL: "rGu2aeQO", OriginalURL: "https://www.google.com"},
+		},
+		{
+			name:   "Invalid destination changes nothing",
+			update: UrlUpdate{OriginalURL: &invalidDestination, Expiration: &noExpiration},
+			want:   Url{ShortenURL: "rGu2aeQO", OriginalURL: "https://www.google.com", Expiration: expiration},
+			err:    NewInvalidInputError(`original URL scheme "javascript" is not allowed`),
+		},
+		{
+			name:   "Past expiration",
+			update: UrlUpdate{OriginalURL: &destination, Expiration: &pastExpiration},
+			want:   Url{ShortenURL: "rGu2aeQO", OriginalURL: "https://www.google.com", Expiration: expiration},
+			err:    NewInvalidInputError("expiration date is in the past"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := Url{ShortenURL: "rGu2aeQO", OriginalURL: "https://www.google.com", Expiration: expiration}
+
+			err := u.Apply(NewURLValidator(DefaultValidationRules()), tt.update)
+			if tt.err == nil && err != nil {
+				t.Errorf("Apply() = %v, want nil", err)
+			}
+
+			if tt.err != nil && !errors.Is(err, tt.err) {
+				t.Errorf("Apply() = %v, want %v", err, tt.err)
+			}
+
+			if !reflect.DeepEqual(u, tt.want) {
+				t.Errorf("Apply() url = %v, want %v", u, tt.want)
+			}
+		})
+	}
+}
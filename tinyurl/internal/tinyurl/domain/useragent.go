@@ -0,0 +1,84 @@
+package domain
+
+import "strings"
+
+// Device types reported by DeviceType
+const (
+	DeviceDesktop = "desktop"
+	DeviceMobile  = "mobile"
+	DeviceTablet  = "tablet"
+	DeviceBot     = "bot"
+	DeviceUnknown = "unknown"
+)
+
+var botMarkers = []string{"bot", "crawler", "spider", "curl/", "wget/", "python-requests", "go-http-client", "headless"}
+
+// browserMarkers : Order matters, most browsers also announce Safari or Chrome
+var browserMarkers = []struct {
+	marker string
+	family string
+}{
+	{"edg/", "Edge"},
+	{"opr/", "Opera"},
+	{"opera", "Opera"},
+	{"samsungbrowser", "Samsung Internet"},
+	{"firefox/", "Firefox"},
+	{"fxios", "Firefox"},
+	{"crios", "Chrome"},
+	{"chrome/", "Chrome"},
+	{"safari/", "Safari"},
+	{"msie", "Internet Explorer"},
+	{"trident/", "Internet Explorer"},
+}
+
+// BrowserFamily : Coarse classification of a User-Agent header
+func BrowserFamily(userAgent string) string {
+	lower := strings.ToLower(userAgent)
+
+	if lower == "" {
+		return "Unknown"
+	}
+
+	if isBot(lower) {
+		return "Bot"
+	}
+
+	for _, browser := range browserMarkers {
+		if strings.Contains(lower, browser.marker) {
+			return browser.family
+		}
+	}
+
+	return "Other"
+}
+
+// DeviceType : Desktop, mobile, tablet or bot, from a User-Agent header
+func DeviceType(userAgent string) string {
+	lower := strings.ToLower(userAgent)
+
+	switch {
+	case lower == "":
+		return DeviceUnknown
+	case isBot(lower):
+		return DeviceBot
+	case strings.Contains(lower, "ipad") || strings.Contains(lower, "tablet"):
+		return DeviceTablet
+	case strings.Contains(lower, "mobi") || strings.Contains(lower, "iphone"):
+		return DeviceMobile
+	case strings.Contains(lower, "android"):
+		// Android tablets do not announce Mobile
+		return DeviceTablet
+	default:
+		return DeviceDesktop
+	}
+}
+
+func isBot(lowerUserAgent string) bool {
+	for _, marker := range botMarkers {
+		if strings.Contains(lowerUserAgent, marker) {
+			return true
+		}
+	}
+
+	return false
+}
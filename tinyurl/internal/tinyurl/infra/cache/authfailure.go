@@ -0,0 +1,60 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	redis "github.com/redis/go-redis/v9"
+)
+
+const authFailureKeyPrefix = "tinyurl:authfailure:"
+
+// AuthFailureRedisCounter : Failed authentications of each client IP, counted in a fixed window
+// The window starts with the first failure and is rounded to the second, a client reaching Limit is blocked until it ends
+type AuthFailureRedisCounter struct {
+	client *redis.Client
+	Limit  int64
+	Window time.Duration
+}
+
+func NewAuthFailureRedisCounter(client *redis.Client, limit int64, window time.Duration) *AuthFailureRedisCounter {
+	return &AuthFailureRedisCounter{
+		client: client,
+		Limit:  limit,
+		Window: window,
+	}
+}
+
+// Blocked : Time left before the client can authenticate again, 0 when it is not blocked
+func (a *AuthFailureRedisCounter) Blocked(ctx context.Context, ip string) (time.Duration, error) {
+	pipeline := a.client.Pipeline()
+	count := pipeline.Get(ctx, authFailureKeyPrefix+ip)
+	ttl := pipeline.PTTL(ctx, authFailureKeyPrefix+ip)
+	if _, err := pipeline.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return 0, err
+	}
+
+	failures, err := count.Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if failures < a.Limit {
+		return 0, nil
+	}
+
+	return max(ttl.Val(), time.Millisecond), nil
+}
+
+// Fail : Count one more failed authentication of the client
+func (a *AuthFailureRedisCounter) Fail(ctx context.Context, ip string) error {
+	pipeline := a.client.TxPipeline()
+	pipeline.Incr(ctx, authFailureKeyPrefix+ip)
+	pipeline.ExpireNX(ctx, authFailureKeyPrefix+ip, a.Window)
+	_, err := pipeline.Exec(ctx)
+	return err
+}
@@ -0,0 +1,213 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/christapa/tinyurl/internal/tinyurl/domain"
+	tinyError "github.com/christapa/tinyurl/pkg/error"
+	"github.com/christapa/tinyurl/pkg/logger"
+	redis "github.com/redis/go-redis/v9"
+)
+
+var (
+	_ domain.UrlRepository = &UrlRedisCache{}
+)
+
+const keyPrefix = "tinyurl:url:"
+
+// notFoundValue : Cached for shorten URLs that do not exist, JSON values can not be empty
+const notFoundValue = ""
+
+type cachedUrl struct {
+	OriginalURL string    `json:"originalUrl"`
+	Counter     int       `json:"counter"`
+	Expiration  time.Time `json:"expiration"`
+	Disabled    bool      `json:"disabled"`
+	Owner       string    `json:"owner"`
+}
+
+// UrlRedisCache : Cache-aside decorator of a UrlRepository
+// Urls are cached for at most TTL and never after their expiration, misses for NegativeTTL
+// Redis errors are logged and the repository is used instead, the cache never fails a request
+// The cached counter is not updated on IncrementCounter, it may be late by up to TTL
+type UrlRedisCache struct {
+	repository  domain.UrlRepository
+	client      *redis.Client
+	TTL         time.Duration
+	NegativeTTL time.Duration
+}
+
+func NewUrlRedisCache(repository domain.UrlRepository, client *redis.Client, ttl time.Duration, negativeTTL time.Duration) *UrlRedisCache {
+	return &UrlRedisCache{
+		repository:  repository,
+		client:      client,
+		TTL:         ttl,
+		NegativeTTL: negativeTTL,
+	}
+}
+
+// StoreUrl : The shorten URL may have been cached as not found
+func (c *UrlRedisCache) StoreUrl(ctx context.Context, url domain.Url) (domain.Url, error) {
+	storedUrl, err := c.repository.StoreUrl(ctx, url)
+	if err != nil {
+		return domain.Url{}, err
+	}
+
+	c.invalidate(ctx, url.ShortenURL)
+
+	return storedUrl, nil
+}
+
+func (c *UrlRedisCache) GetUrl(ctx context.Context, shortUrl string) (domain.Url, error) {
+	url, found, err := c.get(ctx, shortUrl)
+	if err == nil {
+		if !found {
+			return domain.Url{}, tinyError.New(tinyError.NotFound, "not found")
+		}
+		return url, nil
+	}
+
+	if !errors.Is(err, redis.Nil) {
+		logger.Errorf("Failed to read url %s from cache: %v", shortUrl, err)
+	}
+
+	url, err = c.repository.GetUrl(ctx, shortUrl)
+	if tinyError.HasCode(err, tinyError.NotFound) {
+		c.set(ctx, shortUrl, notFoundValue, c.NegativeTTL)
+		return domain.Url{}, err
+	}
+	if err != nil {
+		return domain.Url{}, err
+	}
+
+	c.setUrl(ctx, url)
+
+	return url, nil
+}
+
+// GetUrlByOriginalURL : Not cached, only used when creating urls
+func (c *UrlRedisCache) GetUrlByOriginalURL(ctx context.Context, owner string, originalURL string, expiration time.Time) (domain.Url, error) {
+	return c.repository.GetUrlByOriginalURL(ctx, owner, originalURL, expiration)
+}
+
+// ListUrlsByOwner : Not cached, the list changes with every url created
+func (c *UrlRedisCache) ListUrlsByOwner(ctx context.Context, owner string) ([]domain.Url, error) {
+	return c.repository.ListUrlsByOwner(ctx, owner)
+}
+
+func (c *UrlRedisCache) UpdateUrl(ctx context.Context, url domain.Url) (domain.Url, error) {
+	updatedUrl, err := c.repository.UpdateUrl(ctx, url)
+	if err != nil {
+		return domain.Url{}, err
+	}
+
+	c.invalidate(ctx, url.ShortenURL)
+
+	return updatedUrl, nil
+}
+
+func (c *UrlRedisCache) IncrementCounter(ctx context.Context, shortUrl string) error {
+	return c.repository.IncrementCounter(ctx, shortUrl)
+}
+
+func (c *UrlRedisCache) IncrementCounters(ctx context.Context, increments map[string]int) error {
+	return c.repository.IncrementCounters(ctx, increments)
+}
+
+func (c *UrlRedisCache) DeleteUrl(ctx context.Context, shortUrl string) error {
+	err := c.repository.DeleteUrl(ctx, shortUrl)
+	if err != nil {
+		return err
+	}
+
+	c.invalidate(ctx, shortUrl)
+
+	return nil
+}
+
+// DeleteExpiredUrls : Cached urls are never kept after their expiration, nothing to invalidate
+func (c *UrlRedisCache) DeleteExpiredUrls(ctx context.Context, before time.Time, limit int) (int, error) {
+	return c.repository.DeleteExpiredUrls(ctx, before, limit)
+}
+
+func (c *UrlRedisCache) ArchiveExpiredUrls(ctx context.Context, before time.Time, limit int) (int, error) {
+	return c.repository.ArchiveExpiredUrls(ctx, before, limit)
+}
+
+// get : found is false for a cached miss, redis.Nil is returned when nothing is cached
+func (c *UrlRedisCache) get(ctx context.Context, shortUrl string) (domain.Url, bool, error) {
+	value, err := c.client.Get(ctx, key(shortUrl)).Result()
+	if err != nil {
+		return domain.Url{}, false, err
+	}
+
+	if value == notFoundValue {
+		return domain.Url{}, false, nil
+	}
+
+	var cached cachedUrl
+	err = json.Unmarshal([]byte(value), &cached)
+	if err != nil {
+		return domain.Url{}, false, err
+	}
+
+	return domain.Url{
+		ShortenURL:  shortUrl,
+		OriginalURL: cached.OriginalURL,
+		Counter:     cached.Counter,
+		Expiration:  cached.Expiration,
+		Disabled:    cached.Disabled,
+		Owner:       cached.Owner,
+	}, true, nil
+}
+
+func (c *UrlRedisCache) setUrl(ctx context.Context, url domain.Url) {
+	ttl := c.TTL
+	if !url.Expiration.IsZero() {
+		ttl = min(ttl, time.Until(url.Expiration))
+	}
+
+	// Expired urls are left to the repository
+	if ttl <= 0 {
+		return
+	}
+
+	value, err := json.Marshal(cachedUrl{
+		OriginalURL: url.OriginalURL,
+		Counter:     url.Counter,
+		Expiration:  url.Expiration,
+		Disabled:    url.Disabled,
+		Owner:       url.Owner,
+	})
+	if err != nil {
+		logger.Errorf("Failed to encode url %s for cache: %v", url.ShortenURL, err)
+		return
+	}
+
+	c.set(ctx, url.ShortenURL, string(value), ttl)
+}
+
+func (c *UrlRedisCache) set(ctx context.Context, shortUrl string, value string, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+
+	err := c.client.Set(ctx, key(shortUrl), value, ttl).Err()
+	if err != nil {
+		logger.Errorf("Failed to cache url %s: %v", shortUrl, err)
+	}
+}
+
+func (c *UrlRedisCache) invalidate(ctx context.Context, shortUrl string) {
+	err := c.client.Del(ctx, key(shortUrl)).Err()
+	if err != nil {
+		logger.Errorf("Failed to invalidate cached url %s: %v", shortUrl, err)
+	}
+}
+
+func key(shortUrl string) string {
+	return keyPrefix + shortUrl
+}
@@ -0,0 +1,178 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/christapa/tinyurl/internal/tinyurl/domain"
+	"github.com/christapa/tinyurl/internal/tinyurl/domain/mocks"
+	tinyError "github.com/christapa/tinyurl/pkg/error"
+	redis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	testcontainers "github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+var redisClient *redis.Client
+
+func TestMain(m *testing.M) {
+	ctx := context.Background()
+
+	redisContainer, err := initRedisContainer(ctx)
+	if err != nil {
+		log.Fatalf("Failed to start redis container: %v", err)
+	}
+
+	natPort, err := redisContainer.MappedPort(ctx, "6379")
+	if err != nil {
+		log.Fatalf("Failed to get redis container port: %v", err)
+	}
+
+	redisClient = redis.NewClient(&redis.Options{
+		Network: "tcp",
+		Addr:    fmt.Sprintf("localhost:%d", natPort.Int()),
+	})
+
+	exitVal := m.Run()
+
+	_ = redisContainer.Terminate(ctx)
+	os.Exit(exitVal)
+}
+
+func initRedisContainer(ctx context.Context) (testcontainers.Container, error) {
+	req := testcontainers.ContainerRequest{
+		Image:        "docker.io/redis:7",
+		ExposedPorts: []string{"6379/tcp"},
+		WaitingFor:   wait.ForLog("* Ready to accept connections"),
+	}
+
+	return testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+}
+
+func TestUrlRedisCache_Scenario(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("GetUrlIsCached", func(t *testing.T) {
+		urlRepositoryMock := mocks.NewUrlRepository(t)
+		url := domain.Url{ShortenURL: "cached01", OriginalURL: "https://www.google.com", Counter: 1}
+		urlRepositoryMock.On("GetUrl", mock.Anything, "cached01").Return(url, nil).Once()
+
+		cache := NewUrlRedisCache(urlRepositoryMock, redisClient, time.Minute, time.Minute)
+
+		for range 3 {
+			cachedUrl, err := cache.GetUrl(ctx, "cached01")
+			assert.NoError(t, err)
+			assert.Equal(t, url, cachedUrl)
+		}
+	})
+
+	t.Run("TTLIsCappedByExpiration", func(t *testing.T) {
+		urlRepositoryMock := mocks.NewUrlRepository(t)
+		url := domain.Url{ShortenURL: "expire01", OriginalURL: "https://www.google.com", Expiration: time.Now().Add(2 * time.Second)}
+		urlRepositoryMock.On("GetUrl", mock.Anything, "expire01").Return(url, nil).Once()
+
+		cache := NewUrlRedisCache(urlRepositoryMock, redisClient, time.Hour, time.Minute)
+
+		_, err := cache.GetUrl(ctx, "expire01")
+		assert.NoError(t, err)
+
+		ttl, err := redisClient.TTL(ctx, key("expire01")).Result()
+		assert.NoError(t, err)
+		assert.LessOrEqual(t, ttl, 2*time.Second)
+	})
+
+	t.Run("MissIsCached", func(t *testing.T) {
+		urlRepositoryMock := mocks.NewUrlRepository(t)
+		urlRepositoryMock.On("GetUrl", mock.Anything, "missing1").Return(domain.Url{}, domain.NewNotFoundError()).Once()
+
+		cache := NewUrlRedisCache(urlRepositoryMock, redisClient, time.Minute, time.Minute)
+
+		for range 2 {
+			_, err := cache.GetUrl(ctx, "missing1")
+			assert.True(t, tinyError.HasCode(err, tinyError.NotFound))
+		}
+	})
+
+	t.Run("StoreUrlInvalidatesMiss", func(t *testing.T) {
+		urlRepositoryMock := mocks.NewUrlRepository(t)
+		url := domain.Url{ShortenURL: "missing2", OriginalURL: "https://www.google.com"}
+		urlRepositoryMock.On("GetUrl", mock.Anything, "missing2").Return(domain.Url{}, domain.NewNotFoundError()).Once()
+		urlRepositoryMock.On("StoreUrl", mock.Anything, url).Return(url, nil).Once()
+		urlRepositoryMock.On("GetUrl", mock.Anything, "missing2").Return(url, nil).Once()
+
+		cache := NewUrlRedisCache(urlRepositoryMock, redisClient, time.Minute, time.Minute)
+
+		_, err := cache.GetUrl(ctx, "missing2")
+		assert.Error(t, err)
+
+		_, err = cache.StoreUrl(ctx, url)
+		assert.NoError(t, err)
+
+		cachedUrl, err := cache.GetUrl(ctx, "missing2")
+		assert.NoError(t, err)
+		assert.Equal(t, url, cachedUrl)
+	})
+
+	t.Run("DeleteUrlInvalidates", func(t *testing.T) {
+		urlRepositoryMock := mocks.NewUrlRepository(t)
+		url := domain.Url{ShortenURL: "delete01", OriginalURL: "https://www.google.com"}
+		urlRepositoryMock.On("GetUrl", mock.Anything, "delete01").Return(url, nil).Once()
+		urlRepositoryMock.On("DeleteUrl", mock.Anything, "delete01").Return(nil).Once()
+		urlRepositoryMock.On("GetUrl", mock.Anything, "delete01").Return(domain.Url{}, domain.NewNotFoundError()).Once()
+
+		cache := NewUrlRedisCache(urlRepositoryMock, redisClient, time.Minute, time.Minute)
+
+		_, err := cache.GetUrl(ctx, "delete01")
+		assert.NoError(t, err)
+
+		err = cache.DeleteUrl(ctx, "delete01")
+		assert.NoError(t, err)
+
+		_, err = cache.GetUrl(ctx, "delete01")
+		assert.True(t, tinyError.HasCode(err, tinyError.NotFound))
+	})
+
+	t.Run("UpdateUrlInvalidates", func(t *testing.T) {
+		urlRepositoryMock := mocks.NewUrlRepository(t)
+		url := domain.Url{ShortenURL: "update01", OriginalURL: "https://www.google.com"}
+		updatedUrl := domain.Url{ShortenURL: "update01", OriginalURL: "https://www.bing.com"}
+		urlRepositoryMock.On("GetUrl", mock.Anything, "update01").Return(url, nil).Once()
+		urlRepositoryMock.On("UpdateUrl", mock.Anything, updatedUrl).Return(updatedUrl, nil).Once()
+		urlRepositoryMock.On("GetUrl", mock.Anything, "update01").Return(updatedUrl, nil).Once()
+
+		cache := NewUrlRedisCache(urlRepositoryMock, redisClient, time.Minute, time.Minute)
+
+		_, err := cache.GetUrl(ctx, "update01")
+		assert.NoError(t, err)
+
+		_, err = cache.UpdateUrl(ctx, updatedUrl)
+		assert.NoError(t, err)
+
+		cachedUrl, err := cache.GetUrl(ctx, "update01")
+		assert.NoError(t, err)
+		assert.Equal(t, updatedUrl, cachedUrl)
+	})
+
+	t.Run("RedisDownFallsBackToRepository", func(t *testing.T) {
+		urlRepositoryMock := mocks.NewUrlRepository(t)
+		url := domain.Url{ShortenURL: "fallback", OriginalURL: "https://www.google.com"}
+		urlRepositoryMock.On("GetUrl", mock.Anything, "fallback").Return(url, nil).Once()
+
+		deadClient := redis.NewClient(&redis.Options{Addr: "localhost:1", MaxRetries: -1})
+		defer deadClient.Close()
+
+		cache := NewUrlRedisCache(urlRepositoryMock, deadClient, time.Minute, time.Minute)
+
+		cachedUrl, err := cache.GetUrl(ctx, "fallback")
+		assert.NoError(t, err)
+		assert.Equal(t, url, cachedUrl)
+	})
+}
@@ -0,0 +1,143 @@
+package sql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/christapa/tinyurl/internal/tinyurl/domain"
+	tinyError "github.com/christapa/tinyurl/pkg/error"
+	tinySql "github.com/christapa/tinyurl/pkg/sql"
+)
+
+var (
+	_ domain.ClickRepository      = &ClickSqlRepository{}
+	_ domain.ClickStatsRepository = &ClickSqlRepository{}
+)
+
+// dimensionColumns : Columns clicks can be grouped by, the dimension is never inserted as is in the query
+var dimensionColumns = map[domain.ClickDimension]string{
+	domain.ClickDimensionReferrer:  "referrer",
+	domain.ClickDimensionUserAgent: "user_agent",
+	domain.ClickDimensionLanguage:  "accept_language",
+}
+
+// clickColumns : Number of values inserted per click event
+const clickColumns = 6
+
+// maxClicksPerInsert : Postgres accepts at most 65535 parameters in a statement
+const maxClicksPerInsert = 65535 / clickColumns
+
+// ClickSqlRepository is a struct that represent the ClickRepository
+// Implement ClickRepository interface
+type ClickSqlRepository struct {
+	querier tinySql.Querier
+}
+
+func NewClickSqlRepository(querier tinySql.Querier) *ClickSqlRepository {
+	return &ClickSqlRepository{querier: querier}
+}
+
+// StoreClicks : Insert the events with multi-row INSERTs of at most maxClicksPerInsert events
+// The INSERTs are not in a transaction, the chunks stored before an error are kept
+func (c *ClickSqlRepository) StoreClicks(ctx context.Context, events []domain.ClickEvent) error {
+	for len(events) > 0 {
+		chunk := events[:min(len(events), maxClicksPerInsert)]
+		if err := c.insertClicks(ctx, chunk); err != nil {
+			return err
+		}
+		events = events[len(chunk):]
+	}
+
+	return nil
+}
+
+func (c *ClickSqlRepository) insertClicks(ctx context.Context, events []domain.ClickEvent) error {
+	placeholders := make([]string, 0, len(events))
+	args := make([]any, 0, len(events)*clickColumns)
+	for i, event := range events {
+		offset := i * clickColumns
+		placeholders = append(placeholders, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d)",
+			offset+1, offset+2, offset+3, offset+4, offset+5, offset+6))
+		args = append(args,
+			event.ShortenURL,
+			event.Timestamp,
+			event.Referrer,
+			event.UserAgent,
+			event.ClientIP,
+			event.AcceptLanguage,
+		)
+	}
+
+	_, err := c.querier.ExecContext(ctx,
+		"INSERT INTO clicks (shorten_url, clicked_at, referrer, user_agent, client_ip, accept_language) VALUES "+strings.Join(placeholders, ", "),
+		args...,
+	)
+	if err != nil {
+		return sqlToDomainError(err)
+	}
+
+	return nil
+}
+
+// CountClicks : clicked_at is stored in UTC, so are the bucket starts
+func (c *ClickSqlRepository) CountClicks(ctx context.Context, shortenURL string, from time.Time, to time.Time, bucket domain.StatsBucket) ([]domain.ClickCount, error) {
+	rows, err := c.querier.QueryContext(ctx,
+		"SELECT date_trunc($1, clicked_at) AS bucket, count(*) FROM clicks WHERE shorten_url = $2 AND clicked_at >= $3 AND clicked_at < $4 GROUP BY bucket ORDER BY bucket",
+		string(bucket), shortenURL, from.UTC(), to.UTC())
+	if err != nil {
+		return nil, sqlToDomainError(err)
+	}
+
+	defer rows.Close()
+
+	var counts []domain.ClickCount
+	for rows.Next() {
+		var count domain.ClickCount
+		err = rows.Scan(&count.Start, &count.Count)
+		if err != nil {
+			return nil, tinyError.New(tinyError.Internal, err.Error())
+		}
+		counts = append(counts, count)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, sqlToDomainError(err)
+	}
+
+	return counts, nil
+}
+
+// CountClicksBy : The values are counted, sorted and limited by the database
+func (c *ClickSqlRepository) CountClicksBy(ctx context.Context, shortenURL string, from time.Time, to time.Time, dimension domain.ClickDimension, limit int) ([]domain.Breakdown, error) {
+	column, ok := dimensionColumns[dimension]
+	if !ok {
+		return nil, tinyError.New(tinyError.InvalidArgument, fmt.Sprintf("unknown click dimension %q", dimension))
+	}
+
+	rows, err := c.querier.QueryContext(ctx,
+		fmt.Sprintf("SELECT COALESCE(%s, ''), count(*) FROM clicks WHERE shorten_url = $1 AND clicked_at >= $2 AND clicked_at < $3 GROUP BY 1 ORDER BY 2 DESC, 1 LIMIT $4", column),
+		shortenURL, from.UTC(), to.UTC(), limit)
+	if err != nil {
+		return nil, sqlToDomainError(err)
+	}
+
+	defer rows.Close()
+
+	var breakdown []domain.Breakdown
+	for rows.Next() {
+		var entry domain.Breakdown
+		err = rows.Scan(&entry.Value, &entry.Count)
+		if err != nil {
+			return nil, tinyError.New(tinyError.Internal, err.Error())
+		}
+		breakdown = append(breakdown, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, sqlToDomainError(err)
+	}
+
+	return breakdown, nil
+}
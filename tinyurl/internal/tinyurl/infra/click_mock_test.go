@@ -0,0 +1,143 @@
+package sql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/christapa/tinyurl/internal/tinyurl/domain"
+)
+
+func TestMockStoreClicksHappyPath(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	events := []domain.ClickEvent{
+		domain.NewClickEvent("rGu2aeQO", now, "https://www.bing.com", "curl/8.0", "203.0.113.0", "fr-FR"),
+		domain.NewClickEvent("rGu2aeQO", now, "", "Mozilla/5.0", "203.0.113.0", "en-US"),
+	}
+
+	mock.ExpectExec(`INSERT INTO clicks \(shorten_url, clicked_at, referrer, user_agent, client_ip, accept_language\) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6\), \(\$7, \$8, \$9, \$10, \$11, \$12\)`).
+		WithArgs(
+			"rGu2aeQO", now, "https://www.bing.com", "curl/8.0", "203.0.113.0", "fr-FR",
+			"rGu2aeQO", now, "", "Mozilla/5.0", "203.0.113.0", "en-US",
+		).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	repository := NewClickSqlRepository(db)
+
+	if err := repository.StoreClicks(context.Background(), events); err != nil {
+		t.Fatalf("Failed to store clicks : %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestMockStoreClicksChunked(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	events := make([]domain.ClickEvent, maxClicksPerInsert+1)
+	for i := range events {
+		events[i] = domain.NewClickEvent("rGu2aeQO", now, "", "curl/8.0", "203.0.113.0", "fr-FR")
+	}
+
+	// 65532 parameters, then the last event alone
+	mock.ExpectExec(`INSERT INTO clicks .* \(\$65527, \$65528, \$65529, \$65530, \$65531, \$65532\)$`).
+		WillReturnResult(sqlmock.NewResult(0, maxClicksPerInsert))
+	mock.ExpectExec(`INSERT INTO clicks \(shorten_url, clicked_at, referrer, user_agent, client_ip, accept_language\) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6\)$`).
+		WithArgs("rGu2aeQO", now, "", "curl/8.0", "203.0.113.0", "fr-FR").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repository := NewClickSqlRepository(db)
+
+	if err := repository.StoreClicks(context.Background(), events); err != nil {
+		t.Fatalf("Failed to store clicks : %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestMockCountClicks(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 2)
+
+	rows := sqlmock.NewRows([]string{"bucket", "count"}).
+		AddRow(from, 3).
+		AddRow(from.AddDate(0, 0, 1), 5)
+	mock.ExpectQuery(`SELECT date_trunc\(\$1, clicked_at\) AS bucket, count\(\*\) FROM clicks WHERE shorten_url = \$2 AND clicked_at >= \$3 AND clicked_at < \$4 GROUP BY bucket ORDER BY bucket`).
+		WithArgs("day", "rGu2aeQO", from, to).
+		WillReturnRows(rows)
+
+	repository := NewClickSqlRepository(db)
+
+	counts, err := repository.CountClicks(context.Background(), "rGu2aeQO", from, to, domain.StatsBucketDay)
+	if err != nil {
+		t.Fatalf("Failed to count clicks : %v", err)
+	}
+
+	want := []domain.ClickCount{{Start: from, Count: 3}, {Start: from.AddDate(0, 0, 1), Count: 5}}
+	if len(counts) != len(want) || counts[0] != want[0] || counts[1] != want[1] {
+		t.Errorf("CountClicks() = %v, want %v", counts, want)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestMockCountClicksBy(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	rows := sqlmock.NewRows([]string{"referrer", "count"}).
+		AddRow("https://www.bing.com", 4).
+		AddRow("", 2)
+	mock.ExpectQuery(`SELECT COALESCE\(referrer, ''\), count\(\*\) FROM clicks WHERE shorten_url = \$1 AND clicked_at >= \$2 AND clicked_at < \$3 GROUP BY 1 ORDER BY 2 DESC, 1 LIMIT \$4`).
+		WithArgs("rGu2aeQO", from, to, 100).
+		WillReturnRows(rows)
+
+	repository := NewClickSqlRepository(db)
+
+	breakdown, err := repository.CountClicksBy(context.Background(), "rGu2aeQO", from, to, domain.ClickDimensionReferrer, 100)
+	if err != nil {
+		t.Fatalf("Failed to count clicks by referrer : %v", err)
+	}
+
+	if len(breakdown) != 2 || breakdown[0].Value != "https://www.bing.com" || breakdown[0].Count != 4 {
+		t.Errorf("CountClicksBy() = %v", breakdown)
+	}
+
+	if _, err := repository.CountClicksBy(context.Background(), "rGu2aeQO", from, to, "password", 100); err == nil {
+		t.Errorf("CountClicksBy() with an unknown dimension should fail")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,47 @@
+package sql
+
+import (
+	"context"
+
+	"github.com/christapa/tinyurl/internal/tinyurl/domain"
+	tinyError "github.com/christapa/tinyurl/pkg/error"
+	tinySql "github.com/christapa/tinyurl/pkg/sql"
+)
+
+var (
+	_ domain.Sequence = &SqlSequence{}
+)
+
+// SlugSequenceName : Sequence created by infra/sql/init.sql for the sequence based generators
+const SlugSequenceName = "urls_slug_seq"
+
+// SqlSequence is a Postgres sequence, shared by every instance of the service
+// Implement Sequence interface
+type SqlSequence struct {
+	querier tinySql.Querier
+	name    string
+}
+
+func NewSqlSequence(querier tinySql.Querier, name string) *SqlSequence {
+	return &SqlSequence{querier: querier, name: name}
+}
+
+func (s *SqlSequence) NextValue(ctx context.Context) (uint64, error) {
+	rows, err := s.querier.QueryContext(ctx, "SELECT nextval($1::regclass)", s.name)
+	if err != nil {
+		return 0, sqlToDomainError(err)
+	}
+
+	defer rows.Close()
+
+	if !rows.Next() {
+		return 0, tinyError.New(tinyError.Internal, "sequence returned no value")
+	}
+
+	var value int64
+	if err := rows.Scan(&value); err != nil {
+		return 0, tinyError.New(tinyError.Internal, err.Error())
+	}
+
+	return uint64(value), nil
+}
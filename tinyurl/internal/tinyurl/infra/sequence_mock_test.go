@@ -0,0 +1,34 @@
+package sql
+
+import (
+	"context"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMockSequenceNextValue(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT nextval").
+		WithArgs(SlugSequenceName).
+		WillReturnRows(sqlmock.NewRows([]string{"nextval"}).AddRow(42))
+
+	sequence := NewSqlSequence(db, SlugSequenceName)
+
+	value, err := sequence.NextValue(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to get next value : %v", err)
+	}
+
+	assert.Equal(t, uint64(42), value)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
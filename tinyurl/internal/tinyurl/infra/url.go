@@ -2,6 +2,9 @@ package sql
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/christapa/tinyurl/internal/tinyurl/domain"
@@ -30,15 +33,18 @@ type URL struct {
 	OriginalURL string
 	Counter     int
 	Expiration  time.Time
+	Disabled    bool
+	Owner       string
 }
 
 func (u *TinyUrlSqlRepository) StoreUrl(ctx context.Context, url domain.Url) (domain.Url, error) {
 	result, err := u.querier.ExecContext(ctx,
-		`INSERT INTO urls (shorten_url, original_url, counter, expiration_date) VALUES ($1, $2, $3, $4)`,
+		`INSERT INTO urls (shorten_url, original_url, counter, expiration_date, owner) VALUES ($1, $2, $3, $4, $5)`,
 		url.ShortenURL,
 		url.OriginalURL,
 		url.Counter,
 		url.Expiration,
+		url.Owner,
 	)
 	if err != nil {
 		return domain.Url{}, sqlToDomainError(err)
@@ -58,7 +64,7 @@ func (u *TinyUrlSqlRepository) StoreUrl(ctx context.Context, url domain.Url) (do
 
 func (u *TinyUrlSqlRepository) GetUrl(ctx context.Context, shortUrl string) (domain.Url, error) {
 	rows, err := u.querier.QueryContext(ctx,
-		"SELECT shorten_url, original_url, counter, expiration_date FROM urls WHERE shorten_url = $1 LIMIT 1",
+		"SELECT shorten_url, original_url, counter, expiration_date, disabled, owner FROM urls WHERE shorten_url = $1 LIMIT 1",
 		shortUrl)
 	if err != nil {
 		return domain.Url{}, sqlToDomainError(err)
@@ -71,7 +77,7 @@ func (u *TinyUrlSqlRepository) GetUrl(ctx context.Context, shortUrl string) (dom
 	var found bool
 	if rows.Next() {
 		found = true
-		err = rows.Scan(&url.ShortenURL, &url.OriginalURL, &url.Counter, &url.Expiration)
+		err = rows.Scan(&url.ShortenURL, &url.OriginalURL, &url.Counter, &url.Expiration, &url.Disabled, &url.Owner)
 		if err != nil {
 			return domain.Url{}, tinyError.New(tinyError.Internal, err.Error())
 		}
@@ -84,6 +90,103 @@ func (u *TinyUrlSqlRepository) GetUrl(ctx context.Context, shortUrl string) (dom
 	return url, nil
 }
 
+// GetUrlByOriginalURL : Expired urls are skipped, they may still be waiting for deletion
+// An owner can shorten the same original URL several times with different expirations,
+// the enabled url with the requested expiration is preferred so retries give back the same url
+func (u *TinyUrlSqlRepository) GetUrlByOriginalURL(ctx context.Context, owner string, originalURL string, expiration time.Time) (domain.Url, error) {
+	rows, err := u.querier.QueryContext(ctx,
+		"SELECT shorten_url, original_url, counter, expiration_date, disabled, owner FROM urls WHERE original_url = $1 AND owner = $2 ORDER BY shorten_url",
+		originalURL, owner)
+	if err != nil {
+		return domain.Url{}, sqlToDomainError(err)
+	}
+
+	defer rows.Close()
+
+	var first *domain.Url
+	for rows.Next() {
+		var url domain.Url
+		err = rows.Scan(&url.ShortenURL, &url.OriginalURL, &url.Counter, &url.Expiration, &url.Disabled, &url.Owner)
+		if err != nil {
+			return domain.Url{}, tinyError.New(tinyError.Internal, err.Error())
+		}
+
+		if url.IsExpired() {
+			continue
+		}
+
+		if !url.Disabled && url.HasSameExpiration(expiration) {
+			return url, nil
+		}
+
+		if first == nil {
+			first = &url
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return domain.Url{}, sqlToDomainError(err)
+	}
+
+	if first == nil {
+		return domain.Url{}, tinyError.New(tinyError.NotFound, "not found")
+	}
+
+	return *first, nil
+}
+
+func (u *TinyUrlSqlRepository) ListUrlsByOwner(ctx context.Context, owner string) ([]domain.Url, error) {
+	rows, err := u.querier.QueryContext(ctx,
+		"SELECT shorten_url, original_url, counter, expiration_date, disabled, owner FROM urls WHERE owner = $1 ORDER BY shorten_url",
+		owner)
+	if err != nil {
+		return nil, sqlToDomainError(err)
+	}
+
+	defer rows.Close()
+
+	urls := make([]domain.Url, 0)
+	for rows.Next() {
+		var url domain.Url
+		err = rows.Scan(&url.ShortenURL, &url.OriginalURL, &url.Counter, &url.Expiration, &url.Disabled, &url.Owner)
+		if err != nil {
+			return nil, tinyError.New(tinyError.Internal, err.Error())
+		}
+		urls = append(urls, url)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, sqlToDomainError(err)
+	}
+
+	return urls, nil
+}
+
+// UpdateUrl : Update the original URL, the expiration date and the disabled flag, the counter is left untouched
+func (u *TinyUrlSqlRepository) UpdateUrl(ctx context.Context, url domain.Url) (domain.Url, error) {
+	result, err := u.querier.ExecContext(ctx,
+		"UPDATE urls SET original_url = $2, expiration_date = $3, disabled = $4 WHERE shorten_url = $1",
+		url.ShortenURL,
+		url.OriginalURL,
+		url.Expiration,
+		url.Disabled,
+	)
+	if err != nil {
+		return domain.Url{}, sqlToDomainError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return domain.Url{}, tinyError.New(tinyError.Internal, err.Error())
+	}
+
+	if rowsAffected == 0 {
+		return domain.Url{}, tinyError.New(tinyError.NotFound, "not found")
+	}
+
+	return url, nil
+}
+
 func (u *TinyUrlSqlRepository) IncrementCounter(ctx context.Context, shortUrl string) error {
 	result, err := u.querier.ExecContext(ctx,
 		"UPDATE urls SET counter = counter + 1 WHERE shorten_url = $1",
@@ -106,9 +209,41 @@ func (u *TinyUrlSqlRepository) IncrementCounter(ctx context.Context, shortUrl st
 	return nil
 }
 
+// IncrementCounters : A single UPDATE joined to a VALUES list of the increments
+// Shorten URLs are sorted so concurrent batches lock the rows in the same order
+func (u *TinyUrlSqlRepository) IncrementCounters(ctx context.Context, increments map[string]int) error {
+	if len(increments) == 0 {
+		return nil
+	}
+
+	shortUrls := make([]string, 0, len(increments))
+	for shortUrl := range increments {
+		shortUrls = append(shortUrls, shortUrl)
+	}
+	slices.Sort(shortUrls)
+
+	placeholders := make([]string, 0, len(shortUrls))
+	args := make([]any, 0, len(shortUrls)*2)
+	for i, shortUrl := range shortUrls {
+		placeholders = append(placeholders, fmt.Sprintf("($%d, $%d::integer)", i*2+1, i*2+2))
+		args = append(args, shortUrl, increments[shortUrl])
+	}
+
+	_, err := u.querier.ExecContext(ctx,
+		"UPDATE urls SET counter = urls.counter + increments.count FROM (VALUES "+strings.Join(placeholders, ", ")+") AS increments (shorten_url, count) WHERE urls.shorten_url = increments.shorten_url",
+		args...,
+	)
+	if err != nil {
+		return sqlToDomainError(err)
+	}
+
+	return nil
+}
+
+// DeleteUrl : The clicks are deleted in the same statement, a shorten URL reused later starts without stats
 func (u *TinyUrlSqlRepository) DeleteUrl(ctx context.Context, shortUrl string) error {
 	result, err := u.querier.ExecContext(ctx,
-		"DELETE FROM urls WHERE shorten_url = $1",
+		"WITH deleted_clicks AS (DELETE FROM clicks WHERE shorten_url = $1) DELETE FROM urls WHERE shorten_url = $1",
 		shortUrl,
 	)
 
@@ -127,3 +262,48 @@ func (u *TinyUrlSqlRepository) DeleteUrl(ctx context.Context, shortUrl string) e
 
 	return nil
 }
+
+// expiredUrls : Urls without expiration are stored with the zero date of Go, year 1
+// SKIP LOCKED lets several instances reap at the same time
+const expiredUrls = "SELECT shorten_url FROM urls WHERE expiration_date > '0001-01-01 00:00:00' AND expiration_date < $1 ORDER BY expiration_date LIMIT $2 FOR UPDATE SKIP LOCKED"
+
+// DeleteExpiredUrls : The clicks are deleted in the same statement, a shorten URL reused later starts without stats
+func (u *TinyUrlSqlRepository) DeleteExpiredUrls(ctx context.Context, before time.Time, limit int) (int, error) {
+	result, err := u.querier.ExecContext(ctx,
+		"WITH expired AS ("+expiredUrls+"), "+
+			"expired_clicks AS (DELETE FROM clicks WHERE shorten_url IN (SELECT shorten_url FROM expired)) "+
+			"DELETE FROM urls WHERE shorten_url IN (SELECT shorten_url FROM expired)",
+		before, limit,
+	)
+	if err != nil {
+		return 0, sqlToDomainError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, tinyError.New(tinyError.Internal, err.Error())
+	}
+
+	return int(rowsAffected), nil
+}
+
+// ArchiveExpiredUrls : Move the urls to urls_archive and their clicks to clicks_archive in one statement
+func (u *TinyUrlSqlRepository) ArchiveExpiredUrls(ctx context.Context, before time.Time, limit int) (int, error) {
+	result, err := u.querier.ExecContext(ctx,
+		"WITH expired AS (DELETE FROM urls WHERE shorten_url IN ("+expiredUrls+") RETURNING shorten_url, original_url, counter, expiration_date, disabled, owner), "+
+			"expired_clicks AS (DELETE FROM clicks WHERE shorten_url IN (SELECT shorten_url FROM expired) RETURNING shorten_url, clicked_at, referrer, user_agent, client_ip, accept_language), "+
+			"archived_clicks AS (INSERT INTO clicks_archive (shorten_url, clicked_at, referrer, user_agent, client_ip, accept_language, archived_at) SELECT shorten_url, clicked_at, referrer, user_agent, client_ip, accept_language, $1 FROM expired_clicks) "+
+			"INSERT INTO urls_archive (shorten_url, original_url, counter, expiration_date, disabled, owner, archived_at) SELECT shorten_url, original_url, counter, expiration_date, disabled, owner, $1 FROM expired",
+		before, limit,
+	)
+	if err != nil {
+		return 0, sqlToDomainError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, tinyError.New(tinyError.Internal, err.Error())
+	}
+
+	return int(rowsAffected), nil
+}
@@ -30,7 +30,7 @@ func TestMockStoreUrlHappyPath(t *testing.T) {
 	}
 
 	mock.ExpectExec("INSERT INTO urls").
-		WithArgs(url.ShortenURL, url.OriginalURL, url.Counter, url.Expiration).
+		WithArgs(url.ShortenURL, url.OriginalURL, url.Counter, url.Expiration, url.Owner).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	service := NewUrlSqlRepository(db)
@@ -61,7 +61,7 @@ func TestMockStoreUrlShouldReturnError(t *testing.T) {
 	}
 
 	mock.ExpectExec("INSERT INTO urls").
-		WithArgs(url.ShortenURL, url.OriginalURL, url.Counter, url.Expiration).
+		WithArgs(url.ShortenURL, url.OriginalURL, url.Counter, url.Expiration, url.Owner).
 		WillReturnError(errors.New("some error"))
 
 	service := NewUrlSqlRepository(db)
@@ -86,7 +86,7 @@ func TestMockStoreUrlShouldReturnEmptyRowsAffected(t *testing.T) {
 	}
 
 	mock.ExpectExec("INSERT INTO urls").
-		WithArgs(url.ShortenURL, url.OriginalURL, url.Counter, url.Expiration).
+		WithArgs(url.ShortenURL, url.OriginalURL, url.Counter, url.Expiration, url.Owner).
 		WillReturnResult(sqlmock.NewResult(1, 0))
 
 	service := NewUrlSqlRepository(db)
@@ -172,3 +172,189 @@ func TestDeleteUrlShouldReturnNotFound(t *testing.T) {
 
 	assert.True(t, errors.Is(err, tinyError.New(tinyError.NotFound, "not found")))
 }
+
+func TestMockGetUrlByOriginalURLSkipsExpired(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	originalURL := "https://www.google.com"
+
+	mock.ExpectQuery("SELECT shorten_url, original_url, counter, expiration_date, disabled, owner FROM urls WHERE original_url").
+		WithArgs(originalURL, "test@test.com").
+		WillReturnRows(sqlmock.NewRows([]string{"shorten_url", "original_url", "counter", "expiration_date", "disabled", "owner"}).
+			AddRow("expired", originalURL, 1, time.Now().Add(-time.Hour), false, "test@test.com").
+			AddRow("rGu2aeQO", originalURL, 2, time.Time{}, false, "test@test.com"))
+
+	service := NewUrlSqlRepository(db)
+
+	url, err := service.GetUrlByOriginalURL(context.Background(), "test@test.com", originalURL, time.Time{})
+	if err != nil {
+		t.Fatalf("Failed to get url : %v", err)
+	}
+
+	assert.Equal(t, "rGu2aeQO", url.ShortenURL, "The expired url should be skipped")
+}
+
+func TestMockGetUrlByOriginalURLPrefersSameExpiration(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	originalURL := "https://www.google.com"
+	expiration := time.Now().Add(2 * time.Hour).Truncate(time.Second)
+
+	mock.ExpectQuery("SELECT shorten_url, original_url, counter, expiration_date, disabled, owner FROM urls WHERE original_url").
+		WithArgs(originalURL, "test@test.com").
+		WillReturnRows(sqlmock.NewRows([]string{"shorten_url", "original_url", "counter", "expiration_date", "disabled", "owner"}).
+			AddRow("aaaaaaaa", originalURL, 1, time.Now().Add(time.Hour), false, "test@test.com").
+			AddRow("bbbbbbbb", originalURL, 2, expiration, true, "test@test.com").
+			AddRow("cccccccc", originalURL, 3, expiration, false, "test@test.com"))
+
+	service := NewUrlSqlRepository(db)
+
+	url, err := service.GetUrlByOriginalURL(context.Background(), "test@test.com", originalURL, expiration)
+	if err != nil {
+		t.Fatalf("Failed to get url : %v", err)
+	}
+
+	assert.Equal(t, "cccccccc", url.ShortenURL, "The enabled url with the same expiration should be preferred")
+}
+
+func TestMockUpdateUrlShouldReturnNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	url := domain.Url{
+		ShortenURL:  "rGu2aeQO",
+		OriginalURL: "https://www.google.com",
+		Expiration:  time.Now(),
+	}
+
+	mock.ExpectExec("UPDATE urls SET original_url").
+		WithArgs(url.ShortenURL, url.OriginalURL, url.Expiration, url.Disabled).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	service := NewUrlSqlRepository(db)
+
+	_, err = service.UpdateUrl(context.Background(), url)
+
+	assert.ErrorIs(t, err, tinyError.New(tinyError.NotFound, "not found"))
+}
+
+func TestMockIncrementCountersHappyPath(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec(`UPDATE urls SET counter = urls.counter \+ increments.count FROM \(VALUES \(\$1, \$2::integer\), \(\$3, \$4::integer\)\) AS increments \(shorten_url, count\) WHERE urls.shorten_url = increments.shorten_url`).
+		WithArgs("aY2Pv8", 1, "rGu2aeQO", 42).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	service := NewUrlSqlRepository(db)
+
+	err = service.IncrementCounters(context.Background(), map[string]int{"rGu2aeQO": 42, "aY2Pv8": 1})
+	assert.NoError(t, err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestMockDeleteUrl(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec(`WITH deleted_clicks AS \(DELETE FROM clicks WHERE shorten_url = \$1\) DELETE FROM urls WHERE shorten_url = \$1`).
+		WithArgs("rGu2aeQO").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`WITH deleted_clicks AS \(DELETE FROM clicks WHERE shorten_url = \$1\) DELETE FROM urls WHERE shorten_url = \$1`).
+		WithArgs("aY2Pv8").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	service := NewUrlSqlRepository(db)
+
+	err = service.DeleteUrl(context.Background(), "rGu2aeQO")
+	assert.NoError(t, err)
+
+	err = service.DeleteUrl(context.Background(), "aY2Pv8")
+	assert.ErrorIs(t, err, tinyError.New(tinyError.NotFound, "not found"))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestMockDeleteExpiredUrls(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+
+	mock.ExpectExec(`WITH expired AS \(SELECT shorten_url FROM urls WHERE expiration_date > '0001-01-01 00:00:00' AND expiration_date < \$1 ORDER BY expiration_date LIMIT \$2 FOR UPDATE SKIP LOCKED\), expired_clicks AS \(DELETE FROM clicks WHERE shorten_url IN \(SELECT shorten_url FROM expired\)\) DELETE FROM urls WHERE shorten_url IN \(SELECT shorten_url FROM expired\)`).
+		WithArgs(now, 100).
+		WillReturnResult(sqlmock.NewResult(0, 42))
+
+	service := NewUrlSqlRepository(db)
+
+	deleted, err := service.DeleteExpiredUrls(context.Background(), now, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, 42, deleted)
+}
+
+func TestMockArchiveExpiredUrls(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+
+	mock.ExpectExec(`WITH expired AS \(DELETE FROM urls WHERE shorten_url IN \(SELECT shorten_url FROM urls .*\) RETURNING shorten_url, original_url, counter, expiration_date, disabled, owner\), expired_clicks AS \(DELETE FROM clicks .*\), archived_clicks AS \(INSERT INTO clicks_archive .*\) INSERT INTO urls_archive \(shorten_url, original_url, counter, expiration_date, disabled, owner, archived_at\) SELECT shorten_url, original_url, counter, expiration_date, disabled, owner, \$1 FROM expired`).
+		WithArgs(now, 100).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	service := NewUrlSqlRepository(db)
+
+	archived, err := service.ArchiveExpiredUrls(context.Background(), now, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, archived)
+}
+
+func TestMockListUrlsByOwner(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT shorten_url, original_url, counter, expiration_date, disabled, owner FROM urls WHERE owner = \\$1 ORDER BY shorten_url").
+		WithArgs("test@test.com").
+		WillReturnRows(sqlmock.NewRows([]string{"shorten_url", "original_url", "counter", "expiration_date", "disabled", "owner"}).
+			AddRow("aY2Pv8", "https://www.bing.com", 1, time.Time{}, true, "test@test.com").
+			AddRow("rGu2aeQO", "https://www.google.com", 2, time.Time{}, false, "test@test.com"))
+
+	service := NewUrlSqlRepository(db)
+
+	urls, err := service.ListUrlsByOwner(context.Background(), "test@test.com")
+	assert.NoError(t, err)
+	assert.Len(t, urls, 2)
+	assert.True(t, urls[0].Disabled)
+	assert.Equal(t, "test@test.com", urls[1].Owner)
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/christapa/tinyurl/internal/tinyurl/domain"
+	"github.com/christapa/tinyurl/internal/tinyurl/usecases"
+	"github.com/christapa/tinyurl/pkg/logger"
+)
+
+var (
+	// Ensure ClickRecorder implements the usecases.Clicks interface
+	_ usecases.Clicks = (*ClickRecorder)(nil)
+)
+
+const (
+	// clickFlushTimeout : Maximum duration of one batch insert
+	clickFlushTimeout = 5 * time.Second
+	// defaultClickFlushInterval : Used instead of a zero or negative flush interval
+	defaultClickFlushInterval = time.Second
+)
+
+// ClickRecorder : Store click events in the background, in batches
+// Recording never blocks the redirect, events are dropped when the buffer is full
+type ClickRecorder struct {
+	repository    domain.ClickRepository
+	anonymizeIP   bool
+	batchSize     int
+	flushInterval time.Duration
+
+	events  chan domain.ClickEvent
+	done    chan struct{}
+	mutex   sync.RWMutex
+	closed  bool
+	dropped atomic.Int64
+}
+
+func NewClickRecorder(repository domain.ClickRepository, anonymizeIP bool, bufferSize int, batchSize int, flushInterval time.Duration) *ClickRecorder {
+	if flushInterval <= 0 {
+		flushInterval = defaultClickFlushInterval
+	}
+
+	return &ClickRecorder{
+		repository:    repository,
+		anonymizeIP:   anonymizeIP,
+		batchSize:     max(batchSize, 1),
+		flushInterval: flushInterval,
+		events:        make(chan domain.ClickEvent, bufferSize),
+		done:          make(chan struct{}),
+	}
+}
+
+// Start : Run the writer, Close must be called to stop it
+func (c *ClickRecorder) Start() {
+	go c.run()
+}
+
+// RecordClick : Queue the event for the writer
+func (c *ClickRecorder) RecordClick(event domain.ClickEvent) {
+	if c.anonymizeIP {
+		event = event.Anonymize()
+	}
+
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	if c.closed {
+		c.dropped.Add(1)
+		return
+	}
+
+	select {
+	case c.events <- event:
+	default:
+		c.dropped.Add(1)
+	}
+}
+
+// Dropped : Number of events lost because the buffer was full or the insert failed
+func (c *ClickRecorder) Dropped() int64 {
+	return c.dropped.Load()
+}
+
+// Close : Stop accepting events and wait until the queued ones are stored
+func (c *ClickRecorder) Close(ctx context.Context) error {
+	c.mutex.Lock()
+	if !c.closed {
+		c.closed = true
+		close(c.events)
+	}
+	c.mutex.Unlock()
+
+	select {
+	case <-c.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *ClickRecorder) run() {
+	defer close(c.done)
+
+	ticker := time.NewTicker(c.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]domain.ClickEvent, 0, c.batchSize)
+	for {
+		select {
+		case event, ok := <-c.events:
+			if !ok {
+				c.flush(batch)
+				return
+			}
+
+			batch = append(batch, event)
+			if len(batch) >= c.batchSize {
+				batch = c.flush(batch)
+			}
+		case <-ticker.C:
+			batch = c.flush(batch)
+		}
+	}
+}
+
+// flush : Store the batch and give it back empty
+func (c *ClickRecorder) flush(batch []domain.ClickEvent) []domain.ClickEvent {
+	if len(batch) == 0 {
+		return batch
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), clickFlushTimeout)
+	defer cancel()
+
+	if err := c.repository.StoreClicks(ctx, batch); err != nil {
+		logger.Errorf("Failed to store %d click events: %v", len(batch), err)
+		c.dropped.Add(int64(len(batch)))
+	}
+
+	return batch[:0]
+}
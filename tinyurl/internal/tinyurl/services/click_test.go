@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/christapa/tinyurl/internal/tinyurl/domain"
+	"github.com/christapa/tinyurl/internal/tinyurl/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestClickRecorderStoresOnClose(t *testing.T) {
+	clickRepositoryMock := mocks.NewClickRepository(t)
+
+	now := time.Now()
+	events := []domain.ClickEvent{
+		domain.NewClickEvent("rGu2aeQO", now, "", "curl/8.0", "203.0.113.42", "fr-FR"),
+		domain.NewClickEvent("rGu2aeQO", now, "", "curl/8.0", "203.0.113.43", "fr-FR"),
+	}
+
+	anonymized := []domain.ClickEvent{events[0].Anonymize(), events[1].Anonymize()}
+	clickRepositoryMock.On("StoreClicks", mock.Anything, anonymized).Return(nil).Once()
+
+	recorder := NewClickRecorder(clickRepositoryMock, true, 10, 10, time.Hour)
+	recorder.Start()
+
+	for _, event := range events {
+		recorder.RecordClick(event)
+	}
+
+	err := recorder.Close(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), recorder.Dropped())
+}
+
+func TestClickRecorderStoresFullBatches(t *testing.T) {
+	clickRepositoryMock := mocks.NewClickRepository(t)
+
+	stored := make(chan int, 10)
+	clickRepositoryMock.On("StoreClicks", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		stored <- len(args.Get(1).([]domain.ClickEvent))
+	})
+
+	recorder := NewClickRecorder(clickRepositoryMock, false, 10, 2, time.Hour)
+	recorder.Start()
+
+	for range 2 {
+		recorder.RecordClick(domain.NewClickEvent("rGu2aeQO", time.Now(), "", "", "", ""))
+	}
+
+	select {
+	case size := <-stored:
+		assert.Equal(t, 2, size, "A full batch should be stored without waiting for the interval")
+	case <-time.After(time.Second):
+		t.Fatalf("The full batch was not stored")
+	}
+
+	assert.NoError(t, recorder.Close(context.Background()))
+}
+
+func TestClickRecorderDropsWhenFull(t *testing.T) {
+	clickRepositoryMock := mocks.NewClickRepository(t)
+
+	// The writer is not started, the buffer fills up
+	recorder := NewClickRecorder(clickRepositoryMock, false, 1, 10, time.Hour)
+
+	recorder.RecordClick(domain.NewClickEvent("rGu2aeQO", time.Now(), "", "", "", ""))
+	recorder.RecordClick(domain.NewClickEvent("rGu2aeQO", time.Now(), "", "", "", ""))
+
+	assert.Equal(t, int64(1), recorder.Dropped())
+}
+
+func TestClickRecorderCountsFailedInserts(t *testing.T) {
+	clickRepositoryMock := mocks.NewClickRepository(t)
+
+	clickRepositoryMock.On("StoreClicks", mock.Anything, mock.Anything).Return(errors.New("connection refused"))
+
+	recorder := NewClickRecorder(clickRepositoryMock, false, 10, 10, time.Hour)
+	recorder.Start()
+
+	recorder.RecordClick(domain.NewClickEvent("rGu2aeQO", time.Now(), "", "", "", ""))
+
+	assert.NoError(t, recorder.Close(context.Background()))
+	assert.Equal(t, int64(1), recorder.Dropped())
+}
+
+func TestClickRecorderZeroFlushInterval(t *testing.T) {
+	clickRepositoryMock := mocks.NewClickRepository(t)
+
+	stored := make(chan struct{})
+	clickRepositoryMock.On("StoreClicks", mock.Anything, mock.Anything).Return(nil).Once().Run(func(args mock.Arguments) {
+		close(stored)
+	})
+
+	// The default interval is used, time.NewTicker would panic
+	recorder := NewClickRecorder(clickRepositoryMock, false, 10, 10, 0)
+	recorder.Start()
+
+	recorder.RecordClick(domain.NewClickEvent("rGu2aeQO", time.Now(), "", "", "", ""))
+
+	select {
+	case <-stored:
+	case <-time.After(5 * time.Second):
+		t.Error("The event was not flushed on the default interval")
+	}
+
+	assert.NoError(t, recorder.Close(context.Background()))
+}
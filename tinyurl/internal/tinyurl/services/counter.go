@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"maps"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/christapa/tinyurl/internal/tinyurl/domain"
+	"github.com/christapa/tinyurl/pkg/logger"
+)
+
+var (
+	// Ensure CounterAggregator implements the domain.UrlRepository interface
+	_ domain.UrlRepository = (*CounterAggregator)(nil)
+)
+
+const (
+	// counterFlushTimeout : Maximum duration of one batch update
+	counterFlushTimeout = 5 * time.Second
+	// defaultCounterFlushInterval : Used instead of a zero or negative flush interval
+	defaultCounterFlushInterval = time.Second
+)
+
+// CounterAggregator : UrlRepository decorator summing the counter increments in memory
+// IncrementCounter never waits for the database, the sums are written in batches
+// on an interval or when batchSize shorten URLs are pending
+// Increments are dropped when maxPending shorten URLs are already pending or when the update fails
+type CounterAggregator struct {
+	domain.UrlRepository
+	batchSize     int
+	maxPending    int
+	flushInterval time.Duration
+
+	mutex    sync.Mutex
+	pending  map[string]int
+	total    int64
+	closed   bool
+	flushNow chan struct{}
+	stop     chan struct{}
+	done     chan struct{}
+	dropped  atomic.Int64
+}
+
+func NewCounterAggregator(repository domain.UrlRepository, batchSize int, maxPending int, flushInterval time.Duration) *CounterAggregator {
+	batchSize = max(batchSize, 1)
+	if flushInterval <= 0 {
+		flushInterval = defaultCounterFlushInterval
+	}
+
+	return &CounterAggregator{
+		UrlRepository: repository,
+		batchSize:     batchSize,
+		maxPending:    max(maxPending, batchSize),
+		flushInterval: flushInterval,
+		pending:       make(map[string]int),
+		flushNow:      make(chan struct{}, 1),
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+}
+
+// Start : Run the writer, Close must be called to stop it
+func (c *CounterAggregator) Start() {
+	go c.run()
+}
+
+// IncrementCounter : Add one to the pending sum of the shorten URL
+func (c *CounterAggregator) IncrementCounter(ctx context.Context, shortUrl string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	_, known := c.pending[shortUrl]
+	if c.closed || (!known && len(c.pending) >= c.maxPending) {
+		c.dropped.Add(1)
+		return nil
+	}
+
+	c.pending[shortUrl]++
+	c.total++
+
+	if len(c.pending) >= c.batchSize {
+		select {
+		case c.flushNow <- struct{}{}:
+		default:
+		}
+	}
+
+	return nil
+}
+
+// Pending : Number of increments not written yet
+func (c *CounterAggregator) Pending() int64 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.total
+}
+
+// Dropped : Number of increments lost because too many were pending or the update failed
+func (c *CounterAggregator) Dropped() int64 {
+	return c.dropped.Load()
+}
+
+// Close : Stop accepting increments and write the pending ones
+func (c *CounterAggregator) Close(ctx context.Context) error {
+	c.mutex.Lock()
+	if !c.closed {
+		c.closed = true
+		close(c.stop)
+	}
+	c.mutex.Unlock()
+
+	select {
+	case <-c.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *CounterAggregator) run() {
+	defer close(c.done)
+
+	ticker := time.NewTicker(c.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stop:
+			c.flush()
+			return
+		case <-c.flushNow:
+			c.flush()
+		case <-ticker.C:
+			c.flush()
+		}
+	}
+}
+
+// flush : Take the pending sums and write them batchSize shorten URLs at a time
+func (c *CounterAggregator) flush() {
+	c.mutex.Lock()
+	pending := c.pending
+	c.pending = make(map[string]int)
+	c.mutex.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+
+	batch := make(map[string]int, c.batchSize)
+	for shortUrl, count := range pending {
+		batch[shortUrl] = count
+		if len(batch) >= c.batchSize {
+			c.write(batch)
+			clear(batch)
+		}
+	}
+
+	c.write(batch)
+}
+
+func (c *CounterAggregator) write(batch map[string]int) {
+	if len(batch) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), counterFlushTimeout)
+	defer cancel()
+
+	var count int64
+	for _, increment := range batch {
+		count += int64(increment)
+	}
+
+	err := c.UrlRepository.IncrementCounters(ctx, maps.Clone(batch))
+	if err != nil {
+		logger.Errorf("Failed to increment %d counters: %v", len(batch), err)
+		c.dropped.Add(count)
+	}
+
+	c.mutex.Lock()
+	c.total -= count
+	c.mutex.Unlock()
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/christapa/tinyurl/internal/tinyurl/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCounterAggregatorSumsIncrements(t *testing.T) {
+	urlRepositoryMock := mocks.NewUrlRepository(t)
+	urlRepositoryMock.On("IncrementCounters", mock.Anything, map[string]int{"rGu2aeQO": 100, "aY2Pv8": 1}).Return(nil).Once()
+
+	counters := NewCounterAggregator(urlRepositoryMock, 10, 100, time.Hour)
+	counters.Start()
+
+	var wg sync.WaitGroup
+	for range 100 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = counters.IncrementCounter(context.Background(), "rGu2aeQO")
+		}()
+	}
+	wg.Wait()
+	_ = counters.IncrementCounter(context.Background(), "aY2Pv8")
+
+	assert.Equal(t, int64(101), counters.Pending())
+
+	err := counters.Close(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), counters.Pending())
+	assert.Equal(t, int64(0), counters.Dropped())
+}
+
+func TestCounterAggregatorFlushesFullBatches(t *testing.T) {
+	urlRepositoryMock := mocks.NewUrlRepository(t)
+
+	flushed := make(chan map[string]int, 10)
+	urlRepositoryMock.On("IncrementCounters", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		flushed <- args.Get(1).(map[string]int)
+	})
+
+	counters := NewCounterAggregator(urlRepositoryMock, 2, 100, time.Hour)
+	counters.Start()
+
+	_ = counters.IncrementCounter(context.Background(), "rGu2aeQO")
+	_ = counters.IncrementCounter(context.Background(), "aY2Pv8")
+
+	select {
+	case batch := <-flushed:
+		assert.Equal(t, map[string]int{"rGu2aeQO": 1, "aY2Pv8": 1}, batch)
+	case <-time.After(time.Second):
+		t.Fatalf("The full batch should be written before the interval")
+	}
+
+	err := counters.Close(context.Background())
+	assert.NoError(t, err)
+}
+
+func TestCounterAggregatorDrops(t *testing.T) {
+	urlRepositoryMock := mocks.NewUrlRepository(t)
+	urlRepositoryMock.On("IncrementCounters", mock.Anything, map[string]int{"rGu2aeQO": 2}).Return(errors.New("database is down")).Once()
+
+	// Nothing is written before Close, only one shorten URL can be pending
+	counters := NewCounterAggregator(urlRepositoryMock, 1, 1, time.Hour)
+
+	_ = counters.IncrementCounter(context.Background(), "rGu2aeQO")
+	_ = counters.IncrementCounter(context.Background(), "rGu2aeQO")
+	_ = counters.IncrementCounter(context.Background(), "aY2Pv8")
+	assert.Equal(t, int64(1), counters.Dropped())
+
+	counters.Start()
+	err := counters.Close(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), counters.Dropped())
+	assert.Equal(t, int64(0), counters.Pending())
+
+	_ = counters.IncrementCounter(context.Background(), "rGu2aeQO")
+	assert.Equal(t, int64(4), counters.Dropped())
+}
+
+func TestCounterAggregatorZeroFlushInterval(t *testing.T) {
+	urlRepositoryMock := mocks.NewUrlRepository(t)
+
+	flushed := make(chan struct{})
+	urlRepositoryMock.On("IncrementCounters", mock.Anything, map[string]int{"rGu2aeQO": 1}).Return(nil).Once().Run(func(args mock.Arguments) {
+		close(flushed)
+	})
+
+	// The default interval is used, time.NewTicker would panic
+	counters := NewCounterAggregator(urlRepositoryMock, 10, 100, 0)
+	counters.Start()
+
+	_ = counters.IncrementCounter(context.Background(), "rGu2aeQO")
+
+	select {
+	case <-flushed:
+	case <-time.After(5 * time.Second):
+		t.Error("The increment was not written on the default interval")
+	}
+
+	assert.NoError(t, counters.Close(context.Background()))
+}
@@ -0,0 +1,183 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/christapa/tinyurl/internal/tinyurl/domain"
+	"github.com/christapa/tinyurl/pkg/logger"
+)
+
+// reaperBatchTimeout : Maximum duration of one batch delete
+const reaperBatchTimeout = 30 * time.Second
+
+// ReaperRun : Result of one pass of the ExpirationReaper
+type ReaperRun struct {
+	StartedAt time.Time
+	Duration  time.Duration
+	Reaped    int
+	Batches   int
+	Err       error
+}
+
+// ReaperStats : Metrics of the ExpirationReaper since it started
+type ReaperStats struct {
+	Runs        int64         `json:"runs"`
+	Failures    int64         `json:"failures"`
+	TotalReaped int64         `json:"totalReaped"`
+	LastReaped  int           `json:"lastReaped"`
+	LastRunAt   time.Time     `json:"lastRunAt"`
+	LastRunTook time.Duration `json:"lastRunTook"`
+	LastError   string        `json:"lastError,omitempty"`
+}
+
+// ExpirationReaper : Delete or archive the expired urls in the background
+// Each run deletes batches of batchSize urls until none is left or maxBatches were deleted,
+// so one run never holds the table for long
+type ExpirationReaper struct {
+	repository domain.UrlRepository
+	interval   time.Duration
+	batchSize  int
+	maxBatches int
+	archive    bool
+
+	stop  chan struct{}
+	done  chan struct{}
+	once  sync.Once
+	mutex sync.Mutex
+	stats ReaperStats
+}
+
+func NewExpirationReaper(repository domain.UrlRepository, interval time.Duration, batchSize int, maxBatches int, archive bool) *ExpirationReaper {
+	return &ExpirationReaper{
+		repository: repository,
+		interval:   interval,
+		batchSize:  max(batchSize, 1),
+		maxBatches: max(maxBatches, 1),
+		archive:    archive,
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+}
+
+// Start : Run every interval, Close must be called to stop it
+// A zero interval disables the reaper
+func (r *ExpirationReaper) Start() {
+	if r.interval <= 0 {
+		close(r.done)
+		return
+	}
+
+	go r.run()
+}
+
+// Close : Wait for the current run to end
+func (r *ExpirationReaper) Close(ctx context.Context) error {
+	r.once.Do(func() {
+		close(r.stop)
+	})
+
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Stats : Copy of the metrics
+func (r *ExpirationReaper) Stats() ReaperStats {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.stats
+}
+
+// Reap : One run, the urls expired before now are removed
+func (r *ExpirationReaper) Reap(ctx context.Context, now time.Time) ReaperRun {
+	run := ReaperRun{StartedAt: now}
+	start := time.Now()
+
+	for run.Batches < r.maxBatches {
+		reaped, err := r.reapBatch(ctx, now)
+		if err != nil {
+			run.Err = err
+			break
+		}
+
+		run.Batches++
+		run.Reaped += reaped
+
+		if reaped < r.batchSize {
+			break
+		}
+	}
+
+	run.Duration = time.Since(start)
+	r.record(run)
+
+	return run
+}
+
+func (r *ExpirationReaper) reapBatch(ctx context.Context, now time.Time) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, reaperBatchTimeout)
+	defer cancel()
+
+	if r.archive {
+		return r.repository.ArchiveExpiredUrls(ctx, now, r.batchSize)
+	}
+
+	return r.repository.DeleteExpiredUrls(ctx, now, r.batchSize)
+}
+
+func (r *ExpirationReaper) record(run ReaperRun) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.stats.Runs++
+	r.stats.TotalReaped += int64(run.Reaped)
+	r.stats.LastReaped = run.Reaped
+	r.stats.LastRunAt = run.StartedAt
+	r.stats.LastRunTook = run.Duration
+	r.stats.LastError = ""
+
+	if run.Err != nil {
+		r.stats.Failures++
+		r.stats.LastError = run.Err.Error()
+		logger.Errorf("Expiration reaper failed after %d urls: %v", run.Reaped, run.Err)
+		return
+	}
+
+	if run.Reaped > 0 {
+		logger.Infof("Expiration reaper removed %d urls in %d batches (%s)", run.Reaped, run.Batches, run.Duration)
+	}
+}
+
+func (r *ExpirationReaper) run() {
+	defer close(r.done)
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// A run in progress is interrupted on Close
+	go func() {
+		select {
+		case <-r.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+			r.Reap(ctx, time.Now())
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/christapa/tinyurl/internal/tinyurl/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestExpirationReaperReapsInBatches(t *testing.T) {
+	urlRepositoryMock := mocks.NewUrlRepository(t)
+
+	now := time.Now()
+	urlRepositoryMock.On("DeleteExpiredUrls", mock.Anything, now, 100).Return(100, nil).Twice()
+	urlRepositoryMock.On("DeleteExpiredUrls", mock.Anything, now, 100).Return(42, nil).Once()
+
+	reaper := NewExpirationReaper(urlRepositoryMock, time.Hour, 100, 10, false)
+
+	run := reaper.Reap(context.Background(), now)
+	assert.NoError(t, run.Err)
+	assert.Equal(t, 242, run.Reaped)
+	assert.Equal(t, 3, run.Batches)
+
+	stats := reaper.Stats()
+	assert.Equal(t, int64(1), stats.Runs)
+	assert.Equal(t, int64(242), stats.TotalReaped)
+	assert.Equal(t, 242, stats.LastReaped)
+}
+
+func TestExpirationReaperStopsAfterMaxBatches(t *testing.T) {
+	urlRepositoryMock := mocks.NewUrlRepository(t)
+
+	now := time.Now()
+	urlRepositoryMock.On("ArchiveExpiredUrls", mock.Anything, now, 10).Return(10, nil).Times(2)
+
+	reaper := NewExpirationReaper(urlRepositoryMock, time.Hour, 10, 2, true)
+
+	run := reaper.Reap(context.Background(), now)
+	assert.NoError(t, run.Err)
+	assert.Equal(t, 20, run.Reaped)
+	assert.Equal(t, 2, run.Batches)
+}
+
+func TestExpirationReaperRecordsFailures(t *testing.T) {
+	urlRepositoryMock := mocks.NewUrlRepository(t)
+
+	now := time.Now()
+	urlRepositoryMock.On("DeleteExpiredUrls", mock.Anything, now, 10).Return(10, nil).Once()
+	urlRepositoryMock.On("DeleteExpiredUrls", mock.Anything, now, 10).Return(0, errors.New("database is down")).Once()
+
+	reaper := NewExpirationReaper(urlRepositoryMock, time.Hour, 10, 5, false)
+
+	run := reaper.Reap(context.Background(), now)
+	assert.Error(t, run.Err)
+	assert.Equal(t, 10, run.Reaped)
+
+	stats := reaper.Stats()
+	assert.Equal(t, int64(1), stats.Failures)
+	assert.Equal(t, "database is down", stats.LastError)
+}
+
+func TestExpirationReaperRunsOnInterval(t *testing.T) {
+	urlRepositoryMock := mocks.NewUrlRepository(t)
+
+	reaped := make(chan struct{}, 10)
+	urlRepositoryMock.On("DeleteExpiredUrls", mock.Anything, mock.Anything, 10).Return(0, nil).Run(func(args mock.Arguments) {
+		reaped <- struct{}{}
+	})
+
+	reaper := NewExpirationReaper(urlRepositoryMock, 10*time.Millisecond, 10, 5, false)
+	reaper.Start()
+
+	select {
+	case <-reaped:
+	case <-time.After(time.Second):
+		t.Fatalf("The reaper should run every interval")
+	}
+
+	err := reaper.Close(context.Background())
+	assert.NoError(t, err)
+}
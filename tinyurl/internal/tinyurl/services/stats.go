@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/christapa/tinyurl/internal/tinyurl/domain"
+	"github.com/christapa/tinyurl/internal/tinyurl/usecases"
+)
+
+const (
+	// maxBreakdownEntries : Number of referrers, browsers, devices and languages returned
+	maxBreakdownEntries = 10
+	// maxBreakdownValues : Number of most clicked raw values read per dimension before they are regrouped
+	maxBreakdownValues = 1000
+)
+
+var (
+	// Ensure StatsService implements the usecases.Stats interface
+	_ usecases.Stats = (*StatsService)(nil)
+)
+
+type StatsService struct {
+	urls       usecases.URL
+	repository domain.ClickStatsRepository
+}
+
+func NewStatsService(urls usecases.URL, repository domain.ClickStatsRepository) *StatsService {
+	return &StatsService{
+		urls:       urls,
+		repository: repository,
+	}
+}
+
+// GetURLStats : Clicks of the url over [from, to)
+// Unknown and expired urls are not found, like for a redirect
+// Only the owner can read the stats of a url
+func (s *StatsService) GetURLStats(ctx context.Context, owner string, shortUrl string, from time.Time, to time.Time, bucket domain.StatsBucket) (domain.ClickStats, error) {
+	if err := domain.ValidateStatsRange(from, to, bucket); err != nil {
+		return domain.ClickStats{}, err
+	}
+
+	url, err := s.urls.GetURLMetadata(ctx, shortUrl)
+	if err != nil {
+		return domain.ClickStats{}, err
+	}
+
+	if !url.IsOwnedBy(owner) {
+		return domain.ClickStats{}, domain.NewNotOwnerError()
+	}
+
+	counts, err := s.repository.CountClicks(ctx, url.ShortenURL, from, to, bucket)
+	if err != nil {
+		return domain.ClickStats{}, err
+	}
+
+	referrers, err := s.repository.CountClicksBy(ctx, url.ShortenURL, from, to, domain.ClickDimensionReferrer, maxBreakdownValues)
+	if err != nil {
+		return domain.ClickStats{}, err
+	}
+
+	userAgents, err := s.repository.CountClicksBy(ctx, url.ShortenURL, from, to, domain.ClickDimensionUserAgent, maxBreakdownValues)
+	if err != nil {
+		return domain.ClickStats{}, err
+	}
+
+	languages, err := s.repository.CountClicksBy(ctx, url.ShortenURL, from, to, domain.ClickDimensionLanguage, maxBreakdownValues)
+	if err != nil {
+		return domain.ClickStats{}, err
+	}
+
+	return domain.ClickStats{
+		Url:       url,
+		From:      from,
+		To:        to,
+		Bucket:    bucket,
+		Clicks:    domain.FillClickCounts(counts, from, to, bucket),
+		Referrers: domain.RegroupBreakdown(referrers, domain.ReferrerGroup, maxBreakdownEntries),
+		Browsers:  domain.RegroupBreakdown(userAgents, domain.BrowserFamily, maxBreakdownEntries),
+		Devices:   domain.RegroupBreakdown(userAgents, domain.DeviceType, maxBreakdownEntries),
+		Languages: domain.RegroupBreakdown(languages, domain.PrimaryLanguage, maxBreakdownEntries),
+	}, nil
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/christapa/tinyurl/internal/tinyurl/domain"
+	"github.com/christapa/tinyurl/internal/tinyurl/domain/mocks"
+	usecaseMocks "github.com/christapa/tinyurl/internal/tinyurl/usecases/mocks"
+	tinyError "github.com/christapa/tinyurl/pkg/error"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	firefoxDesktop = "Mozilla/5.0 (X11; Linux x86_64; rv:125.0) Gecko/20100101 Firefox/125.0"
+	safariIPhone   = "Mozilla/5.0 (iPhone; CPU iPhone OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Mobile/15E148 Safari/604.1"
+)
+
+func TestGetURLStats(t *testing.T) {
+	urlServiceMock := usecaseMocks.NewURL(t)
+	clickRepositoryMock := mocks.NewClickStatsRepository(t)
+
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 3)
+	url := domain.Url{ShortenURL: "rGu2aeQO", OriginalURL: "https://www.google.com", Counter: 9, Owner: testOwner}
+
+	urlServiceMock.On("GetURLMetadata", mock.Anything, "rGu2aeQO").Return(url, nil).Once()
+	clickRepositoryMock.On("CountClicks", mock.Anything, "rGu2aeQO", from, to, domain.StatsBucketDay).
+		Return([]domain.ClickCount{{Start: from.AddDate(0, 0, 1), Count: 9}}, nil).Once()
+	clickRepositoryMock.On("CountClicksBy", mock.Anything, "rGu2aeQO", from, to, domain.ClickDimensionReferrer, maxBreakdownValues).
+		Return([]domain.Breakdown{{Value: "https://www.bing.com", Count: 5}, {Value: "", Count: 4}}, nil).Once()
+	clickRepositoryMock.On("CountClicksBy", mock.Anything, "rGu2aeQO", from, to, domain.ClickDimensionUserAgent, maxBreakdownValues).
+		Return([]domain.Breakdown{{Value: firefoxDesktop, Count: 4}, {Value: safariIPhone, Count: 3}, {Value: "curl/8.0", Count: 2}}, nil).Once()
+	clickRepositoryMock.On("CountClicksBy", mock.Anything, "rGu2aeQO", from, to, domain.ClickDimensionLanguage, maxBreakdownValues).
+		Return([]domain.Breakdown{{Value: "fr-FR,fr;q=0.9", Count: 4}, {Value: "en-US", Count: 3}, {Value: "fr", Count: 2}}, nil).Once()
+
+	service := NewStatsService(urlServiceMock, clickRepositoryMock)
+
+	stats, err := service.GetURLStats(context.Background(), testOwner, "rGu2aeQO", from, to, domain.StatsBucketDay)
+	assert.NoError(t, err)
+	assert.Equal(t, url, stats.Url)
+	assert.Equal(t, []domain.ClickCount{
+		{Start: from, Count: 0},
+		{Start: from.AddDate(0, 0, 1), Count: 9},
+		{Start: from.AddDate(0, 0, 2), Count: 0},
+	}, stats.Clicks)
+	assert.Equal(t, []domain.Breakdown{{Value: "https://www.bing.com", Count: 5}, {Value: domain.DirectReferrer, Count: 4}}, stats.Referrers)
+	assert.Equal(t, []domain.Breakdown{{Value: "Firefox", Count: 4}, {Value: "Safari", Count: 3}, {Value: "Bot", Count: 2}}, stats.Browsers)
+	assert.Equal(t, []domain.Breakdown{{Value: domain.DeviceDesktop, Count: 4}, {Value: domain.DeviceMobile, Count: 3}, {Value: domain.DeviceBot, Count: 2}}, stats.Devices)
+	assert.Equal(t, []domain.Breakdown{{Value: "fr", Count: 6}, {Value: "en", Count: 3}}, stats.Languages)
+}
+
+func TestGetURLStatsInvalidRange(t *testing.T) {
+	urlServiceMock := usecaseMocks.NewURL(t)
+	clickRepositoryMock := mocks.NewClickStatsRepository(t)
+
+	service := NewStatsService(urlServiceMock, clickRepositoryMock)
+
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := service.GetURLStats(context.Background(), testOwner, "rGu2aeQO", from, from.AddDate(0, 0, -1), domain.StatsBucketDay)
+	assert.True(t, tinyError.HasCode(err, tinyError.InvalidArgument))
+
+	_, err = service.GetURLStats(context.Background(), testOwner, "rGu2aeQO", from, from.AddDate(1, 0, 0), domain.StatsBucketHour)
+	assert.True(t, tinyError.HasCode(err, tinyError.InvalidArgument))
+}
+
+func TestGetURLStatsNotFound(t *testing.T) {
+	urlServiceMock := usecaseMocks.NewURL(t)
+	clickRepositoryMock := mocks.NewClickStatsRepository(t)
+
+	urlServiceMock.On("GetURLMetadata", mock.Anything, "unknown").Return(domain.Url{}, domain.NewNotFoundError()).Once()
+
+	service := NewStatsService(urlServiceMock, clickRepositoryMock)
+
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	_, err := service.GetURLStats(context.Background(), testOwner, "unknown", from, from.AddDate(0, 0, 1), domain.StatsBucketDay)
+	assert.True(t, tinyError.HasCode(err, tinyError.NotFound))
+}
+
+func TestGetURLStatsNotOwner(t *testing.T) {
+	urlServiceMock := usecaseMocks.NewURL(t)
+	clickRepositoryMock := mocks.NewClickStatsRepository(t)
+
+	url := domain.Url{ShortenURL: "rGu2aeQO", OriginalURL: "https://www.google.com", Owner: testOwner}
+	urlServiceMock.On("GetURLMetadata", mock.Anything, "rGu2aeQO").Return(url, nil).Twice()
+
+	service := NewStatsService(urlServiceMock, clickRepositoryMock)
+
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	for _, owner := range []string{"", "other@test.com"} {
+		_, err := service.GetURLStats(context.Background(), owner, "rGu2aeQO", from, from.AddDate(0, 0, 1), domain.StatsBucketDay)
+		assert.True(t, tinyError.HasCode(err, tinyError.PermissionDenied), owner)
+	}
+}
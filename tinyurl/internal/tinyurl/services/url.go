@@ -6,51 +6,208 @@ import (
 
 	"github.com/christapa/tinyurl/internal/tinyurl/domain"
 	"github.com/christapa/tinyurl/internal/tinyurl/usecases"
+	tinyError "github.com/christapa/tinyurl/pkg/error"
 	"github.com/christapa/tinyurl/pkg/logger"
 )
 
+// maxSlugAttempts : Number of shorten URLs tried for an original URL before giving up
+const maxSlugAttempts = 5
+
 var (
 	// Ensure UrlService implements the domain.UrlUseCase interface
 	_ usecases.URL = (*UrlService)(nil)
 )
 
 type UrlService struct {
-	repository domain.UrlRepository
+	repository       domain.UrlRepository
+	generator        domain.ShortenURLGenerator
+	expirationPolicy domain.ExpirationPolicy
+	validator        domain.URLValidator
 }
 
-func NewUrlService(repository domain.UrlRepository) *UrlService {
+func NewUrlService(repository domain.UrlRepository, generator domain.ShortenURLGenerator, expirationPolicy domain.ExpirationPolicy, validator domain.URLValidator) *UrlService {
 	return &UrlService{
-		repository: repository,
+		repository:       repository,
+		generator:        generator,
+		expirationPolicy: expirationPolicy,
+		validator:        validator,
 	}
 }
 
 // CreateShortenUrl : Shorten the url and store it in the database
-func (u *UrlService) CreateShortenUrl(ctx context.Context, url string, expiration time.Time) (domain.Url, error) {
-	newUrl, err := domain.NewURL(url, expiration)
+// An existing url for the same original URL is given back, following the expiration policy
+// The alias is used as shorten URL when given, otherwise the generator picks one
+// When the generated shorten URL is already used by another original URL, a salted one is tried
+// Only the urls of the owner are given back
+func (u *UrlService) CreateShortenUrl(ctx context.Context, owner string, url string, alias string, expiration time.Time) (domain.Url, bool, error) {
+	if alias != "" {
+		return u.createAliasUrl(ctx, owner, url, alias, expiration)
+	}
+
+	existingUrl, err := u.repository.GetUrlByOriginalURL(ctx, owner, url, expiration)
+	if err != nil && !tinyError.HasCode(err, tinyError.NotFound) {
+		return domain.Url{}, false, err
+	}
+
+	if err == nil {
+		reusedUrl, reused, err := u.reuseUrl(ctx, existingUrl, expiration)
+		if err != nil || reused {
+			return reusedUrl, false, err
+		}
+	}
+
+	for attempt := 0; attempt < maxSlugAttempts; attempt++ {
+		input := url
+		if attempt > 0 {
+			input, err = domain.SaltURL(url, owner)
+			if err != nil {
+				return domain.Url{}, false, err
+			}
+		}
+
+		shortenURL, err := u.generator.GenerateShortenURL(ctx, input)
+		if err != nil {
+			return domain.Url{}, false, err
+		}
+
+		if domain.IsReservedShortenURL(shortenURL) {
+			logger.Infof("Generated shorten URL %s is reserved, retrying", shortenURL)
+			continue
+		}
+
+		newUrl, err := domain.NewURL(u.validator, shortenURL, url, expiration)
+		if err != nil {
+			return domain.Url{}, false, err
+		}
+		newUrl.Owner = owner
+
+		_, err = u.repository.StoreUrl(ctx, newUrl)
+		if err == nil {
+			return newUrl, true, nil
+		}
+
+		if !tinyError.HasCode(err, tinyError.AlreadyExists) {
+			return domain.Url{}, false, err
+		}
+
+		existingUrl, err := u.repository.GetUrl(ctx, newUrl.ShortenURL)
+		if err != nil {
+			return domain.Url{}, false, err
+		}
+
+		// Same original URL stored concurrently, an expired record is treated as a collision
+		if existingUrl.OriginalURL == newUrl.OriginalURL && existingUrl.Owner == owner && !existingUrl.IsExpired() {
+			reusedUrl, reused, err := u.reuseUrl(ctx, existingUrl, expiration)
+			if err != nil || reused {
+				return reusedUrl, false, err
+			}
+		}
+
+		logger.Infof("Shorten URL %s collides with another original URL, retrying", newUrl.ShortenURL)
+	}
+
+	logger.Errorf("No free shorten URL for %s after %d attempts", url, maxSlugAttempts)
+	return domain.Url{}, false, domain.NewSlugCollisionError()
+}
+
+func (u *UrlService) createAliasUrl(ctx context.Context, owner string, url string, alias string, expiration time.Time) (domain.Url, bool, error) {
+	newUrl, err := domain.NewAliasURL(u.validator, alias, url, expiration)
 	if err != nil {
-		return domain.Url{}, err
+		return domain.Url{}, false, err
 	}
+	newUrl.Owner = owner
 
 	_, err = u.repository.StoreUrl(ctx, newUrl)
+	if err == nil {
+		return newUrl, true, nil
+	}
+
+	if !tinyError.HasCode(err, tinyError.AlreadyExists) {
+		return domain.Url{}, false, err
+	}
+
+	existingUrl, err := u.repository.GetUrl(ctx, alias)
 	if err != nil {
-		return domain.Url{}, err
+		return domain.Url{}, false, domain.NewAliasTakenError(alias)
+	}
+
+	// Retrying the same alias request is not a conflict
+	if existingUrl.OriginalURL == url && existingUrl.Owner == owner && !existingUrl.IsExpired() && !existingUrl.Disabled && existingUrl.HasSameExpiration(expiration) {
+		return existingUrl, false, nil
 	}
 
-	return newUrl, nil
+	// An expired alias not reaped yet is free
+	if existingUrl.IsExpired() {
+		return u.replaceExpiredAlias(ctx, newUrl)
+	}
+
+	return domain.Url{}, false, domain.NewAliasTakenError(alias)
+}
+
+func (u *UrlService) replaceExpiredAlias(ctx context.Context, newUrl domain.Url) (domain.Url, bool, error) {
+	err := u.repository.DeleteUrl(ctx, newUrl.ShortenURL)
+	if err != nil && !tinyError.HasCode(err, tinyError.NotFound) {
+		return domain.Url{}, false, err
+	}
+
+	_, err = u.repository.StoreUrl(ctx, newUrl)
+	if tinyError.HasCode(err, tinyError.AlreadyExists) {
+		return domain.Url{}, false, domain.NewAliasTakenError(newUrl.ShortenURL)
+	}
+	if err != nil {
+		return domain.Url{}, false, err
+	}
+
+	return newUrl, true, nil
+}
+
+// reuseUrl : Apply the expiration policy to an existing url of the requested original URL
+// Returns false when a new url has to be created, a disabled url is never given back
+func (u *UrlService) reuseUrl(ctx context.Context, existingUrl domain.Url, expiration time.Time) (domain.Url, bool, error) {
+	if existingUrl.Disabled {
+		return domain.Url{}, false, nil
+	}
+
+	if existingUrl.HasSameExpiration(expiration) {
+		return existingUrl, true, nil
+	}
+
+	switch u.expirationPolicy {
+	case domain.ExpirationPolicyExtend:
+		if !existingUrl.ExpiresBefore(expiration) {
+			return existingUrl, true, nil
+		}
+
+		existingUrl.Expiration = expiration
+		updatedUrl, err := u.repository.UpdateUrl(ctx, existingUrl)
+		if err != nil {
+			return domain.Url{}, false, err
+		}
+
+		return updatedUrl, true, nil
+	case domain.ExpirationPolicyNew:
+		return domain.Url{}, false, nil
+	default:
+		return domain.Url{}, false, domain.NewExpirationConflictError()
+	}
 }
 
 // GetOriginalUrl : Give back the original url from the shorten url
 // Each time the original url is retrieved, the counter is incremented
-// If the url is expired, it is deleted from the database
+// Expired urls are not found, the ExpirationReaper deletes them
+// Disabled urls are kept but do not redirect
 func (u *UrlService) GetOriginalUrl(ctx context.Context, shortUrl string) (string, error) {
 	url, err := u.repository.GetUrl(ctx, shortUrl)
 	if err != nil {
 		return "", err
 	}
 
-	err = u.assessUrl(url)
-	if err != nil {
-		return "", err
+	if url.IsExpired() {
+		return "", domain.NewNotFoundError()
+	}
+
+	if url.Disabled {
+		return "", domain.NewDisabledError()
 	}
 
 	err = u.repository.IncrementCounter(ctx, shortUrl)
@@ -69,23 +226,69 @@ func (u *UrlService) GetURLMetadata(ctx context.Context, shortUrl string) (domai
 		return domain.Url{}, err
 	}
 
-	err = u.assessUrl(url)
+	if url.IsExpired() {
+		return domain.Url{}, domain.NewNotFoundError()
+	}
+
+	return url, nil
+}
+
+// UpdateUrl : Change the original URL or the expiration date of an existing url
+func (u *UrlService) UpdateUrl(ctx context.Context, owner string, shortUrl string, update domain.UrlUpdate) (domain.Url, error) {
+	url, err := u.getOwnedUrl(ctx, owner, shortUrl)
 	if err != nil {
 		return domain.Url{}, err
 	}
 
-	return url, nil
+	err = url.Apply(u.validator, update)
+	if err != nil {
+		return domain.Url{}, err
+	}
+
+	return u.repository.UpdateUrl(ctx, url)
 }
 
-func (u *UrlService) assessUrl(url domain.Url) error {
-	if url.IsExpired() {
-		err := u.repository.DeleteUrl(context.Background(), url.ShortenURL)
-		if err != nil {
-			return err
-		}
+// SetUrlDisabled : A disabled url answers Gone instead of redirecting, until it is enabled again
+func (u *UrlService) SetUrlDisabled(ctx context.Context, owner string, shortUrl string, disabled bool) (domain.Url, error) {
+	url, err := u.getOwnedUrl(ctx, owner, shortUrl)
+	if err != nil {
+		return domain.Url{}, err
+	}
 
-		return domain.NewNotFoundError()
+	if url.Disabled == disabled {
+		return url, nil
 	}
 
-	return nil
+	url.Disabled = disabled
+
+	return u.repository.UpdateUrl(ctx, url)
+}
+
+// DeleteUrl : The shorten URL can be used again afterwards
+func (u *UrlService) DeleteUrl(ctx context.Context, owner string, shortUrl string) error {
+	_, err := u.getOwnedUrl(ctx, owner, shortUrl)
+	if err != nil {
+		return err
+	}
+
+	return u.repository.DeleteUrl(ctx, shortUrl)
+}
+
+// ListUrls : Urls of the owner, expired ones included until they are reaped
+func (u *UrlService) ListUrls(ctx context.Context, owner string) ([]domain.Url, error) {
+	return u.repository.ListUrlsByOwner(ctx, owner)
+}
+
+// getOwnedUrl : Only the owner can manage a url, urls without owner can not be managed
+func (u *UrlService) getOwnedUrl(ctx context.Context, owner string, shortUrl string) (domain.Url, error) {
+	url, err := u.GetURLMetadata(ctx, shortUrl)
+	if err != nil {
+		return domain.Url{}, err
+	}
+
+	if !url.IsOwnedBy(owner) {
+		return domain.Url{}, domain.NewNotOwnerError()
+	}
+
+	return url, nil
 }
@@ -2,38 +2,572 @@ package services
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/christapa/tinyurl/internal/tinyurl/domain"
 	"github.com/christapa/tinyurl/internal/tinyurl/domain/mocks"
+	tinyError "github.com/christapa/tinyurl/pkg/error"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 // TODO : Increase coverage
 
+const testOwner = "test@test.com"
+
+var testValidator = domain.NewURLValidator(domain.DefaultValidationRules())
+
+// salted : Matches the input given to the generator after a collision, whatever its nonce
+func salted(url string, owner string) interface{} {
+	return mock.MatchedBy(func(input string) bool {
+		return strings.HasPrefix(input, url+"\x00"+owner+"\x00")
+	})
+}
+
 func TestCreateShortenURLHappyPath(t *testing.T) {
 	urlRepositoryMock := mocks.NewUrlRepository(t)
 
-	url, err := domain.NewURL("https://www.google.com", time.Time{})
+	generator, err := domain.NewHashShortenURLGenerator(8)
+	if err != nil {
+		t.Errorf("Error while creating the generator: %v", err)
+	}
+
+	url, err := domain.NewURL(testValidator, "rGu2aeQO", "https://www.google.com", time.Time{})
 	if err != nil {
 		t.Errorf("Error while creating a new URL: %v", err)
 	}
 
 	ctx := context.Background()
 
+	urlRepositoryMock.On("GetUrlByOriginalURL", ctx, "", url.OriginalURL, url.Expiration).Return(domain.Url{}, tinyError.New(tinyError.NotFound, "not found"))
 	urlRepositoryMock.On("StoreUrl", ctx, url).Return(url, nil)
 
 	// Create a new URL service
-	urlService := NewUrlService(urlRepositoryMock)
+	urlService := NewUrlService(urlRepositoryMock, generator, domain.ExpirationPolicyReject, testValidator)
 
 	// Call the CreateShortenUrl function
-	urlCreated, err := urlService.CreateShortenUrl(ctx, url.OriginalURL, url.Expiration)
+	urlCreated, created, err := urlService.CreateShortenUrl(ctx, "", url.OriginalURL, "", url.Expiration)
 	if err != nil {
 		t.Errorf("Error while creating a shorten URL: %v", err)
 	}
 
 	urlRepositoryMock.AssertExpectations(t)
 
+	assert.True(t, created, "The url should be created")
 	assert.Equal(t, url, urlCreated, "The two urls should be equal (Create/Created)")
 }
+
+func TestCreateShortenURLRetriesOnCollision(t *testing.T) {
+	urlRepositoryMock := mocks.NewUrlRepository(t)
+	generatorMock := mocks.NewShortenURLGenerator(t)
+
+	ctx := context.Background()
+	originalURL := "https://www.google.com"
+
+	urlRepositoryMock.On("GetUrlByOriginalURL", ctx, "", originalURL, time.Time{}).Return(domain.Url{}, tinyError.New(tinyError.NotFound, "not found"))
+	generatorMock.On("GenerateShortenURL", ctx, originalURL).Return("collide", nil)
+	generatorMock.On("GenerateShortenURL", ctx, salted(originalURL, "")).Return("fresh", nil)
+
+	collidingUrl := domain.Url{ShortenURL: "collide", OriginalURL: originalURL}
+	freshUrl := domain.Url{ShortenURL: "fresh", OriginalURL: originalURL}
+
+	urlRepositoryMock.On("StoreUrl", ctx, collidingUrl).Return(domain.Url{}, tinyError.New(tinyError.AlreadyExists, "duplicate key"))
+	urlRepositoryMock.On("GetUrl", ctx, "collide").Return(domain.Url{ShortenURL: "collide", OriginalURL: "https://www.bing.com"}, nil)
+	urlRepositoryMock.On("StoreUrl", ctx, freshUrl).Return(freshUrl, nil)
+
+	urlService := NewUrlService(urlRepositoryMock, generatorMock, domain.ExpirationPolicyReject, testValidator)
+
+	urlCreated, created, err := urlService.CreateShortenUrl(ctx, "", originalURL, "", time.Time{})
+	assert.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, freshUrl, urlCreated, "The salted shorten URL should be used after a collision")
+}
+
+func TestCreateShortenURLConcurrentSameOriginalURL(t *testing.T) {
+	urlRepositoryMock := mocks.NewUrlRepository(t)
+	generatorMock := mocks.NewShortenURLGenerator(t)
+
+	ctx := context.Background()
+	originalURL := "https://www.google.com"
+
+	urlRepositoryMock.On("GetUrlByOriginalURL", ctx, "", originalURL, time.Time{}).Return(domain.Url{}, tinyError.New(tinyError.NotFound, "not found"))
+	generatorMock.On("GenerateShortenURL", ctx, originalURL).Return("existing", nil)
+
+	existingUrl := domain.Url{ShortenURL: "existing", OriginalURL: originalURL, Counter: 3}
+
+	urlRepositoryMock.On("StoreUrl", ctx, domain.Url{ShortenURL: "existing", OriginalURL: originalURL}).Return(domain.Url{}, tinyError.New(tinyError.AlreadyExists, "duplicate key"))
+	urlRepositoryMock.On("GetUrl", ctx, "existing").Return(existingUrl, nil)
+
+	urlService := NewUrlService(urlRepositoryMock, generatorMock, domain.ExpirationPolicyReject, testValidator)
+
+	urlCreated, created, err := urlService.CreateShortenUrl(ctx, "", originalURL, "", time.Time{})
+	assert.NoError(t, err)
+	assert.False(t, created, "A url stored by another request should be reused")
+	assert.Equal(t, existingUrl, urlCreated)
+}
+
+func TestCreateShortenURLCollisionsExhausted(t *testing.T) {
+	urlRepositoryMock := mocks.NewUrlRepository(t)
+	generatorMock := mocks.NewShortenURLGenerator(t)
+
+	ctx := context.Background()
+	originalURL := "https://www.google.com"
+
+	urlRepositoryMock.On("GetUrlByOriginalURL", ctx, "", originalURL, time.Time{}).Return(domain.Url{}, tinyError.New(tinyError.NotFound, "not found"))
+	generatorMock.On("GenerateShortenURL", ctx, mock.Anything).Return("collide", nil)
+
+	urlRepositoryMock.On("StoreUrl", ctx, mock.Anything).Return(domain.Url{}, tinyError.New(tinyError.AlreadyExists, "duplicate key"))
+	urlRepositoryMock.On("GetUrl", ctx, "collide").Return(domain.Url{ShortenURL: "collide", OriginalURL: "https://www.bing.com"}, nil)
+
+	urlService := NewUrlService(urlRepositoryMock, generatorMock, domain.ExpirationPolicyReject, testValidator)
+
+	_, _, err := urlService.CreateShortenUrl(ctx, "", originalURL, "", time.Time{})
+	assert.ErrorIs(t, err, domain.NewSlugCollisionError())
+
+	urlRepositoryMock.AssertNumberOfCalls(t, "StoreUrl", maxSlugAttempts)
+}
+
+func TestCreateShortenURLManyLinksForOneURL(t *testing.T) {
+	urlRepositoryMock := mocks.NewUrlRepository(t)
+	generator, err := domain.NewHashShortenURLGenerator(8)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	originalURL := "https://www.google.com"
+	stored := map[string]domain.Url{}
+	var latest domain.Url
+
+	urlRepositoryMock.On("GetUrlByOriginalURL", ctx, testOwner, originalURL, mock.Anything).Return(func(context.Context, string, string, time.Time) (domain.Url, error) {
+		if latest.ShortenURL == "" {
+			return domain.Url{}, tinyError.New(tinyError.NotFound, "not found")
+		}
+		return latest, nil
+	})
+	urlRepositoryMock.On("StoreUrl", ctx, mock.Anything).Return(func(_ context.Context, url domain.Url) (domain.Url, error) {
+		if _, ok := stored[url.ShortenURL]; ok {
+			return domain.Url{}, tinyError.New(tinyError.AlreadyExists, "duplicate key")
+		}
+		stored[url.ShortenURL] = url
+		latest = url
+		return url, nil
+	})
+	urlRepositoryMock.On("GetUrl", ctx, mock.Anything).Return(func(_ context.Context, shortenURL string) (domain.Url, error) {
+		return stored[shortenURL], nil
+	})
+
+	urlService := NewUrlService(urlRepositoryMock, generator, domain.ExpirationPolicyNew, testValidator)
+
+	// Each expiration date differs from the previous one, so a new shorten URL is created every time
+	links := 3 * maxSlugAttempts
+	expiration := time.Now().Add(time.Hour).Truncate(time.Second)
+	for i := 0; i < links; i++ {
+		_, created, err := urlService.CreateShortenUrl(ctx, testOwner, originalURL, "", expiration.Add(time.Duration(i)*time.Hour))
+		assert.NoError(t, err)
+		assert.True(t, created)
+	}
+
+	assert.Len(t, stored, links)
+}
+
+func TestCreateShortenURLRetryWithAnotherExpirationIsIdempotent(t *testing.T) {
+	urlRepositoryMock := mocks.NewUrlRepository(t)
+	generator, err := domain.NewHashShortenURLGenerator(8)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	originalURL := "https://www.google.com"
+	firstExpiration := time.Now().Add(time.Hour).Truncate(time.Second)
+	secondExpiration := firstExpiration.Add(time.Hour)
+
+	stored := map[string]domain.Url{
+		"existing": {ShortenURL: "existing", OriginalURL: originalURL, Expiration: firstExpiration, Owner: testOwner},
+	}
+
+	urlRepositoryMock.On("GetUrlByOriginalURL", ctx, testOwner, originalURL, mock.Anything).Return(func(_ context.Context, _ string, _ string, expiration time.Time) (domain.Url, error) {
+		for _, url := range stored {
+			if url.HasSameExpiration(expiration) {
+				return url, nil
+			}
+		}
+		return stored["existing"], nil
+	})
+	urlRepositoryMock.On("StoreUrl", ctx, mock.Anything).Return(func(_ context.Context, url domain.Url) (domain.Url, error) {
+		if _, ok := stored[url.ShortenURL]; ok {
+			return domain.Url{}, tinyError.New(tinyError.AlreadyExists, "duplicate key")
+		}
+		stored[url.ShortenURL] = url
+		return url, nil
+	})
+	urlRepositoryMock.On("GetUrl", ctx, mock.Anything).Return(func(_ context.Context, shortenURL string) (domain.Url, error) {
+		return stored[shortenURL], nil
+	}).Maybe()
+
+	urlService := NewUrlService(urlRepositoryMock, generator, domain.ExpirationPolicyNew, testValidator)
+
+	first, created, err := urlService.CreateShortenUrl(ctx, testOwner, originalURL, "", secondExpiration)
+	assert.NoError(t, err)
+	assert.True(t, created)
+
+	retried, created, err := urlService.CreateShortenUrl(ctx, testOwner, originalURL, "", secondExpiration)
+	assert.NoError(t, err)
+	assert.False(t, created, "The retried request should reuse the url it created")
+	assert.Equal(t, first.ShortenURL, retried.ShortenURL)
+	assert.Len(t, stored, 2)
+}
+
+func TestCreateShortenURLWithAlias(t *testing.T) {
+	urlRepositoryMock := mocks.NewUrlRepository(t)
+	generatorMock := mocks.NewShortenURLGenerator(t)
+
+	ctx := context.Background()
+
+	aliasUrl := domain.Url{ShortenURL: "spring-sale", OriginalURL: "https://www.google.com"}
+
+	urlRepositoryMock.On("StoreUrl", ctx, aliasUrl).Return(aliasUrl, nil)
+
+	urlService := NewUrlService(urlRepositoryMock, generatorMock, domain.ExpirationPolicyReject, testValidator)
+
+	urlCreated, created, err := urlService.CreateShortenUrl(ctx, "", aliasUrl.OriginalURL, "spring-sale", time.Time{})
+	assert.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, aliasUrl, urlCreated, "The alias should be used as shorten URL")
+
+	generatorMock.AssertNotCalled(t, "GenerateShortenURL", mock.Anything, mock.Anything)
+}
+
+func TestCreateShortenURLWithTakenAlias(t *testing.T) {
+	urlRepositoryMock := mocks.NewUrlRepository(t)
+	generatorMock := mocks.NewShortenURLGenerator(t)
+
+	ctx := context.Background()
+
+	aliasUrl := domain.Url{ShortenURL: "spring-sale", OriginalURL: "https://www.google.com"}
+
+	urlRepositoryMock.On("StoreUrl", ctx, aliasUrl).Return(domain.Url{}, tinyError.New(tinyError.AlreadyExists, "duplicate key"))
+	urlRepositoryMock.On("GetUrl", ctx, "spring-sale").Return(domain.Url{ShortenURL: "spring-sale", OriginalURL: "https://www.bing.com"}, nil)
+
+	urlService := NewUrlService(urlRepositoryMock, generatorMock, domain.ExpirationPolicyReject, testValidator)
+
+	_, _, err := urlService.CreateShortenUrl(ctx, "", aliasUrl.OriginalURL, "spring-sale", time.Time{})
+	assert.ErrorIs(t, err, domain.NewAliasTakenError("spring-sale"))
+}
+
+func TestCreateShortenURLReplacesExpiredAlias(t *testing.T) {
+	urlRepositoryMock := mocks.NewUrlRepository(t)
+	generatorMock := mocks.NewShortenURLGenerator(t)
+
+	ctx := context.Background()
+
+	aliasUrl := domain.Url{ShortenURL: "spring-sale", OriginalURL: "https://www.google.com"}
+	expiredUrl := domain.Url{ShortenURL: "spring-sale", OriginalURL: "https://www.bing.com", Expiration: time.Now().Add(-time.Hour)}
+
+	urlRepositoryMock.On("StoreUrl", ctx, aliasUrl).Return(domain.Url{}, tinyError.New(tinyError.AlreadyExists, "duplicate key")).Once()
+	urlRepositoryMock.On("GetUrl", ctx, "spring-sale").Return(expiredUrl, nil)
+	urlRepositoryMock.On("DeleteUrl", ctx, "spring-sale").Return(nil)
+	urlRepositoryMock.On("StoreUrl", ctx, aliasUrl).Return(aliasUrl, nil).Once()
+
+	urlService := NewUrlService(urlRepositoryMock, generatorMock, domain.ExpirationPolicyReject, testValidator)
+
+	urlCreated, created, err := urlService.CreateShortenUrl(ctx, "", aliasUrl.OriginalURL, "spring-sale", time.Time{})
+	assert.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, aliasUrl, urlCreated)
+}
+
+func TestCreateShortenURLSkipsReservedShortenURL(t *testing.T) {
+	urlRepositoryMock := mocks.NewUrlRepository(t)
+	generatorMock := mocks.NewShortenURLGenerator(t)
+
+	ctx := context.Background()
+	originalURL := "https://www.google.com"
+
+	urlRepositoryMock.On("GetUrlByOriginalURL", ctx, "", originalURL, time.Time{}).Return(domain.Url{}, tinyError.New(tinyError.NotFound, "not found"))
+	generatorMock.On("GenerateShortenURL", ctx, originalURL).Return("health", nil)
+	generatorMock.On("GenerateShortenURL", ctx, salted(originalURL, "")).Return("fresh", nil)
+
+	freshUrl := domain.Url{ShortenURL: "fresh", OriginalURL: originalURL}
+
+	urlRepositoryMock.On("StoreUrl", ctx, freshUrl).Return(freshUrl, nil)
+
+	urlService := NewUrlService(urlRepositoryMock, generatorMock, domain.ExpirationPolicyReject, testValidator)
+
+	urlCreated, created, err := urlService.CreateShortenUrl(ctx, "", originalURL, "", time.Time{})
+	assert.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, freshUrl, urlCreated, "A reserved shorten URL should never be stored")
+}
+
+func TestCreateShortenURLExistingOriginalURL(t *testing.T) {
+	ctx := context.Background()
+	originalURL := "https://www.google.com"
+	expiration := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	tests := []struct {
+		name       string
+		policy     domain.ExpirationPolicy
+		existing   domain.Url
+		expiration time.Time
+		want       domain.Url
+		created    bool
+		err        error
+	}{
+		{
+			name:       "Same expiration",
+			policy:     domain.ExpirationPolicyReject,
+			existing:   domain.Url{ShortenURL: "existing", OriginalURL: originalURL, Expiration: expiration},
+			expiration: expiration,
+			want:       domain.Url{ShortenURL: "existing", OriginalURL: originalURL, Expiration: expiration},
+			created:    false,
+		},
+		{
+			name:       "Different expiration rejected",
+			policy:     domain.ExpirationPolicyReject,
+			existing:   domain.Url{ShortenURL: "existing", OriginalURL: originalURL},
+			expiration: expiration,
+			want:       domain.Url{},
+			err:        domain.NewExpirationConflictError(),
+		},
+		{
+			name:       "Shorter expiration kept when extending",
+			policy:     domain.ExpirationPolicyExtend,
+			existing:   domain.Url{ShortenURL: "existing", OriginalURL: originalURL},
+			expiration: expiration,
+			want:       domain.Url{ShortenURL: "existing", OriginalURL: originalURL},
+			created:    false,
+		},
+		{
+			name:       "Different expiration with a new shorten URL",
+			policy:     domain.ExpirationPolicyNew,
+			existing:   domain.Url{ShortenURL: "existing", OriginalURL: originalURL},
+			expiration: expiration,
+			want:       domain.Url{ShortenURL: "fresh", OriginalURL: originalURL, Expiration: expiration},
+			created:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			urlRepositoryMock := mocks.NewUrlRepository(t)
+			generatorMock := mocks.NewShortenURLGenerator(t)
+
+			urlRepositoryMock.On("GetUrlByOriginalURL", ctx, "", originalURL, tt.expiration).Return(tt.existing, nil)
+			if tt.created {
+				generatorMock.On("GenerateShortenURL", ctx, originalURL).Return("fresh", nil)
+				urlRepositoryMock.On("StoreUrl", ctx, tt.want).Return(tt.want, nil)
+			}
+
+			urlService := NewUrlService(urlRepositoryMock, generatorMock, tt.policy, testValidator)
+
+			got, created, err := urlService.CreateShortenUrl(ctx, "", originalURL, "", tt.expiration)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.created, created)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCreateShortenURLExtendsExpiration(t *testing.T) {
+	urlRepositoryMock := mocks.NewUrlRepository(t)
+	generatorMock := mocks.NewShortenURLGenerator(t)
+
+	ctx := context.Background()
+	originalURL := "https://www.google.com"
+	expiration := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	existingUrl := domain.Url{ShortenURL: "existing", OriginalURL: originalURL, Expiration: expiration}
+	extendedUrl := domain.Url{ShortenURL: "existing", OriginalURL: originalURL}
+
+	urlRepositoryMock.On("GetUrlByOriginalURL", ctx, "", originalURL, time.Time{}).Return(existingUrl, nil)
+	urlRepositoryMock.On("UpdateUrl", ctx, extendedUrl).Return(extendedUrl, nil)
+
+	urlService := NewUrlService(urlRepositoryMock, generatorMock, domain.ExpirationPolicyExtend, testValidator)
+
+	got, created, err := urlService.CreateShortenUrl(ctx, "", originalURL, "", time.Time{})
+	assert.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, extendedUrl, got, "A url without expiration outlives the existing one")
+}
+
+func TestGetOriginalUrlExpiredIsNotDeleted(t *testing.T) {
+	urlRepositoryMock := mocks.NewUrlRepository(t)
+	generatorMock := mocks.NewShortenURLGenerator(t)
+
+	ctx := context.Background()
+
+	expiredUrl := domain.Url{ShortenURL: "rGu2aeQO", OriginalURL: "https://www.google.com", Expiration: time.Now().Add(-time.Hour)}
+	urlRepositoryMock.On("GetUrl", ctx, "rGu2aeQO").Return(expiredUrl, nil)
+
+	urlService := NewUrlService(urlRepositoryMock, generatorMock, domain.ExpirationPolicyReject, testValidator)
+
+	_, err := urlService.GetOriginalUrl(ctx, "rGu2aeQO")
+	assert.ErrorIs(t, err, domain.NewNotFoundError())
+
+	urlRepositoryMock.AssertNotCalled(t, "DeleteUrl", mock.Anything, mock.Anything)
+	urlRepositoryMock.AssertNotCalled(t, "IncrementCounter", mock.Anything, mock.Anything)
+}
+
+func TestGetOriginalUrlDisabled(t *testing.T) {
+	urlRepositoryMock := mocks.NewUrlRepository(t)
+	generatorMock := mocks.NewShortenURLGenerator(t)
+
+	ctx := context.Background()
+
+	disabledUrl := domain.Url{ShortenURL: "rGu2aeQO", OriginalURL: "https://www.google.com", Disabled: true}
+	urlRepositoryMock.On("GetUrl", ctx, "rGu2aeQO").Return(disabledUrl, nil)
+
+	urlService := NewUrlService(urlRepositoryMock, generatorMock, domain.ExpirationPolicyReject, testValidator)
+
+	_, err := urlService.GetOriginalUrl(ctx, "rGu2aeQO")
+	assert.ErrorIs(t, err, domain.NewDisabledError())
+
+	urlRepositoryMock.AssertNotCalled(t, "IncrementCounter", mock.Anything, mock.Anything)
+}
+
+func TestUpdateUrl(t *testing.T) {
+	urlRepositoryMock := mocks.NewUrlRepository(t)
+	generatorMock := mocks.NewShortenURLGenerator(t)
+
+	ctx := context.Background()
+
+	url := domain.Url{ShortenURL: "rGu2aeQO", OriginalURL: "https://www.google.com", Counter: 3, Owner: testOwner}
+	updatedUrl := domain.Url{ShortenURL: "rGu2aeQO", OriginalURL: "https://www.bing.com", Counter: 3, Owner: testOwner}
+
+	urlRepositoryMock.On("GetUrl", ctx, "rGu2aeQO").Return(url, nil)
+	urlRepositoryMock.On("UpdateUrl", ctx, updatedUrl).Return(updatedUrl, nil).Once()
+
+	urlService := NewUrlService(urlRepositoryMock, generatorMock, domain.ExpirationPolicyReject, testValidator)
+
+	destination := "https://www.bing.com"
+	got, err := urlService.UpdateUrl(ctx, testOwner, "rGu2aeQO", domain.UrlUpdate{OriginalURL: &destination})
+	assert.NoError(t, err)
+	assert.Equal(t, updatedUrl, got)
+
+	invalidDestination := "ftp://www.bing.com"
+	_, err = urlService.UpdateUrl(ctx, testOwner, "rGu2aeQO", domain.UrlUpdate{OriginalURL: &invalidDestination})
+	assert.True(t, tinyError.HasCode(err, tinyError.InvalidArgument))
+}
+
+func TestSetUrlDisabled(t *testing.T) {
+	urlRepositoryMock := mocks.NewUrlRepository(t)
+	generatorMock := mocks.NewShortenURLGenerator(t)
+
+	ctx := context.Background()
+
+	url := domain.Url{ShortenURL: "rGu2aeQO", OriginalURL: "https://www.google.com", Owner: testOwner}
+	disabledUrl := domain.Url{ShortenURL: "rGu2aeQO", OriginalURL: "https://www.google.com", Disabled: true, Owner: testOwner}
+
+	urlRepositoryMock.On("GetUrl", ctx, "rGu2aeQO").Return(url, nil).Once()
+	urlRepositoryMock.On("UpdateUrl", ctx, disabledUrl).Return(disabledUrl, nil).Once()
+	urlRepositoryMock.On("GetUrl", ctx, "rGu2aeQO").Return(disabledUrl, nil).Once()
+
+	urlService := NewUrlService(urlRepositoryMock, generatorMock, domain.ExpirationPolicyReject, testValidator)
+
+	got, err := urlService.SetUrlDisabled(ctx, testOwner, "rGu2aeQO", true)
+	assert.NoError(t, err)
+	assert.True(t, got.Disabled)
+
+	// Disabling twice does not write
+	got, err = urlService.SetUrlDisabled(ctx, testOwner, "rGu2aeQO", true)
+	assert.NoError(t, err)
+	assert.True(t, got.Disabled)
+}
+
+func TestCreateShortenURLDoesNotReuseDisabledUrl(t *testing.T) {
+	urlRepositoryMock := mocks.NewUrlRepository(t)
+	generatorMock := mocks.NewShortenURLGenerator(t)
+
+	ctx := context.Background()
+
+	disabledUrl := domain.Url{ShortenURL: "rGu2aeQO", OriginalURL: "https://www.google.com", Disabled: true}
+	newUrl := domain.Url{ShortenURL: "aY2Pv8", OriginalURL: "https://www.google.com"}
+
+	urlRepositoryMock.On("GetUrlByOriginalURL", ctx, "", "https://www.google.com", time.Time{}).Return(disabledUrl, nil)
+	generatorMock.On("GenerateShortenURL", ctx, "https://www.google.com").Return("aY2Pv8", nil)
+	urlRepositoryMock.On("StoreUrl", ctx, newUrl).Return(newUrl, nil)
+
+	urlService := NewUrlService(urlRepositoryMock, generatorMock, domain.ExpirationPolicyReject, testValidator)
+
+	got, created, err := urlService.CreateShortenUrl(ctx, "", "https://www.google.com", "", time.Time{})
+	assert.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, newUrl, got)
+}
+
+func TestManageUrlOfAnotherOwner(t *testing.T) {
+	urlRepositoryMock := mocks.NewUrlRepository(t)
+	generatorMock := mocks.NewShortenURLGenerator(t)
+
+	ctx := context.Background()
+
+	url := domain.Url{ShortenURL: "rGu2aeQO", OriginalURL: "https://www.google.com", Owner: "other@test.com"}
+	anonymousUrl := domain.Url{ShortenURL: "aY2Pv8", OriginalURL: "https://www.google.com"}
+
+	urlRepositoryMock.On("GetUrl", ctx, "rGu2aeQO").Return(url, nil)
+	urlRepositoryMock.On("GetUrl", ctx, "aY2Pv8").Return(anonymousUrl, nil)
+
+	urlService := NewUrlService(urlRepositoryMock, generatorMock, domain.ExpirationPolicyReject, testValidator)
+
+	destination := "https://www.bing.com"
+	_, err := urlService.UpdateUrl(ctx, testOwner, "rGu2aeQO", domain.UrlUpdate{OriginalURL: &destination})
+	assert.ErrorIs(t, err, domain.NewNotOwnerError())
+
+	_, err = urlService.SetUrlDisabled(ctx, testOwner, "rGu2aeQO", true)
+	assert.ErrorIs(t, err, domain.NewNotOwnerError())
+
+	err = urlService.DeleteUrl(ctx, testOwner, "rGu2aeQO")
+	assert.ErrorIs(t, err, domain.NewNotOwnerError())
+
+	// Urls created before ownership can not be managed
+	err = urlService.DeleteUrl(ctx, "", "aY2Pv8")
+	assert.ErrorIs(t, err, domain.NewNotOwnerError())
+
+	urlRepositoryMock.AssertNotCalled(t, "UpdateUrl", mock.Anything, mock.Anything)
+	urlRepositoryMock.AssertNotCalled(t, "DeleteUrl", mock.Anything, mock.Anything)
+}
+
+func TestCreateShortenURLDoesNotReuseUrlOfAnotherOwner(t *testing.T) {
+	urlRepositoryMock := mocks.NewUrlRepository(t)
+	generatorMock := mocks.NewShortenURLGenerator(t)
+
+	ctx := context.Background()
+
+	otherUrl := domain.Url{ShortenURL: "rGu2aeQO", OriginalURL: "https://www.google.com", Owner: "other@test.com"}
+	newUrl := domain.Url{ShortenURL: "aY2Pv8", OriginalURL: "https://www.google.com", Owner: testOwner}
+
+	urlRepositoryMock.On("GetUrlByOriginalURL", ctx, testOwner, "https://www.google.com", time.Time{}).Return(domain.Url{}, tinyError.New(tinyError.NotFound, "not found"))
+	generatorMock.On("GenerateShortenURL", ctx, "https://www.google.com").Return("rGu2aeQO", nil)
+	generatorMock.On("GenerateShortenURL", ctx, salted("https://www.google.com", testOwner)).Return("aY2Pv8", nil)
+	urlRepositoryMock.On("StoreUrl", ctx, domain.Url{ShortenURL: "rGu2aeQO", OriginalURL: "https://www.google.com", Owner: testOwner}).Return(domain.Url{}, tinyError.New(tinyError.AlreadyExists, "already exists"))
+	urlRepositoryMock.On("GetUrl", ctx, "rGu2aeQO").Return(otherUrl, nil)
+	urlRepositoryMock.On("StoreUrl", ctx, newUrl).Return(newUrl, nil)
+
+	urlService := NewUrlService(urlRepositoryMock, generatorMock, domain.ExpirationPolicyReject, testValidator)
+
+	got, created, err := urlService.CreateShortenUrl(ctx, testOwner, "https://www.google.com", "", time.Time{})
+	assert.NoError(t, err)
+	assert.True(t, created, "The url of another owner is a collision")
+	assert.Equal(t, newUrl, got)
+}
+
+func TestListUrls(t *testing.T) {
+	urlRepositoryMock := mocks.NewUrlRepository(t)
+	generatorMock := mocks.NewShortenURLGenerator(t)
+
+	ctx := context.Background()
+
+	urls := []domain.Url{{ShortenURL: "rGu2aeQO", OriginalURL: "https://www.google.com", Owner: testOwner}}
+	urlRepositoryMock.On("ListUrlsByOwner", ctx, testOwner).Return(urls, nil)
+
+	urlService := NewUrlService(urlRepositoryMock, generatorMock, domain.ExpirationPolicyReject, testValidator)
+
+	got, err := urlService.ListUrls(ctx, testOwner)
+	assert.NoError(t, err)
+	assert.Equal(t, urls, got)
+}
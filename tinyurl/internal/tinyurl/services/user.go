@@ -0,0 +1,49 @@
+package services
+
+import (
+	"context"
+	"runtime"
+
+	"github.com/christapa/tinyurl/internal/tinyurl/domain"
+	"github.com/christapa/tinyurl/internal/tinyurl/usecases"
+)
+
+var (
+	// Ensure UserService implements the usecases.Users interface
+	_ usecases.Users = (*UserService)(nil)
+)
+
+type UserService struct {
+	repository domain.UserRepository
+	// verifications bounds the passwords hashed at the same time, each argon2id hash takes 64 MiB
+	verifications chan struct{}
+}
+
+// NewUserService : maxVerifications is the number of passwords verified at the same time, the number of CPUs when 0
+func NewUserService(repository domain.UserRepository, maxVerifications int) *UserService {
+	if maxVerifications <= 0 {
+		maxVerifications = runtime.NumCPU()
+	}
+
+	return &UserService{
+		repository:    repository,
+		verifications: make(chan struct{}, maxVerifications),
+	}
+}
+
+// Authenticate : Unknown emails and wrong passwords are both rejected without error
+// Waits for a verification slot, the context error is returned when it ends first
+func (u *UserService) Authenticate(ctx context.Context, email string, password string) (bool, error) {
+	if email == "" || password == "" {
+		return false, nil
+	}
+
+	select {
+	case u.verifications <- struct{}{}:
+		defer func() { <-u.verifications }()
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
+
+	return u.repository.IsPasswordMatch(ctx, email, password)
+}
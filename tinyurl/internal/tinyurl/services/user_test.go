@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/christapa/tinyurl/internal/tinyurl/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAuthenticate(t *testing.T) {
+	userRepositoryMock := mocks.NewUserRepository(t)
+
+	ctx := context.Background()
+
+	userRepositoryMock.On("IsPasswordMatch", ctx, "test@test.com", "secret").Return(true, nil)
+	userRepositoryMock.On("IsPasswordMatch", ctx, "test@test.com", "wrong").Return(false, nil)
+
+	userService := NewUserService(userRepositoryMock, 1)
+
+	ok, err := userService.Authenticate(ctx, "test@test.com", "secret")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = userService.Authenticate(ctx, "test@test.com", "wrong")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	// Empty credentials never reach the database
+	ok, err = userService.Authenticate(ctx, "test@test.com", "")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	userRepositoryMock.AssertNotCalled(t, "IsPasswordMatch", mock.Anything, mock.Anything, "")
+}
+
+func TestAuthenticateBoundsConcurrentVerifications(t *testing.T) {
+	userRepositoryMock := mocks.NewUserRepository(t)
+
+	ctx := context.Background()
+
+	verifying := make(chan struct{})
+	release := make(chan struct{})
+	userRepositoryMock.On("IsPasswordMatch", ctx, "test@test.com", "secret").
+		Run(func(mock.Arguments) {
+			close(verifying)
+			<-release
+		}).
+		Return(true, nil).
+		Once()
+
+	userService := NewUserService(userRepositoryMock, 1)
+
+	done := make(chan bool)
+	go func() {
+		ok, _ := userService.Authenticate(ctx, "test@test.com", "secret")
+		done <- ok
+	}()
+	<-verifying
+
+	// The only slot is taken, the second caller gives up with its context
+	waitCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	ok, err := userService.Authenticate(waitCtx, "test@test.com", "other")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.False(t, ok)
+
+	close(release)
+	assert.True(t, <-done)
+}
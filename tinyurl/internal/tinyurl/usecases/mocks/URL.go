@@ -16,34 +16,59 @@ type URL struct {
 	mock.Mock
 }
 
-// CreateShortenUrl provides a mock function with given fields: ctx, url, expiration
-func (_m *URL) CreateShortenUrl(ctx context.Context, url string, expiration time.Time) (domain.Url, error) {
-	ret := _m.Called(ctx, url, expiration)
+// CreateShortenUrl provides a mock function with given fields: ctx, owner, url, alias, expiration
+func (_m *URL) CreateShortenUrl(ctx context.Context, owner string, url string, alias string, expiration time.Time) (domain.Url, bool, error) {
+	ret := _m.Called(ctx, owner, url, alias, expiration)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateShortenUrl")
 	}
 
 	var r0 domain.Url
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) (domain.Url, error)); ok {
-		return rf(ctx, url, expiration)
+	var r1 bool
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, time.Time) (domain.Url, bool, error)); ok {
+		return rf(ctx, owner, url, alias, expiration)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) domain.Url); ok {
-		r0 = rf(ctx, url, expiration)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, time.Time) domain.Url); ok {
+		r0 = rf(ctx, owner, url, alias, expiration)
 	} else {
 		r0 = ret.Get(0).(domain.Url)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
-		r1 = rf(ctx, url, expiration)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, time.Time) bool); ok {
+		r1 = rf(ctx, owner, url, alias, expiration)
 	} else {
-		r1 = ret.Error(1)
+		r1 = ret.Get(1).(bool)
 	}
 
-	return r0, r1
+	if rf, ok := ret.Get(2).(func(context.Context, string, string, string, time.Time) error); ok {
+		r2 = rf(ctx, owner, url, alias, expiration)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// DeleteUrl provides a mock function with given fields: ctx, owner, shortUrl
+func (_m *URL) DeleteUrl(ctx context.Context, owner string, shortUrl string) error {
+	ret := _m.Called(ctx, owner, shortUrl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteUrl")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, owner, shortUrl)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
 }
- 
+
 // GetOriginalUrl provides a mock function with given fields: ctx, shortUrl
 func (_m *URL) GetOriginalUrl(ctx context.Context, shortUrl string) (string, error) {
 	ret := _m.Called(ctx, shortUrl)
@@ -100,6 +125,92 @@ func (_m *URL) GetURLMetadata(ctx context.Context, url string) (domain.Url, erro
 	return r0, r1
 }
 
+// ListUrls provides a mock function with given fields: ctx, owner
+func (_m *URL) ListUrls(ctx context.Context, owner string) ([]domain.Url, error) {
+	ret := _m.Called(ctx, owner)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListUrls")
+	}
+
+	var r0 []domain.Url
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]domain.Url, error)); ok {
+		return rf(ctx, owner)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []domain.Url); ok {
+		r0 = rf(ctx, owner)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Url)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, owner)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetUrlDisabled provides a mock function with given fields: ctx, owner, shortUrl, disabled
+func (_m *URL) SetUrlDisabled(ctx context.Context, owner string, shortUrl string, disabled bool) (domain.Url, error) {
+	ret := _m.Called(ctx, owner, shortUrl, disabled)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetUrlDisabled")
+	}
+
+	var r0 domain.Url
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, bool) (domain.Url, error)); ok {
+		return rf(ctx, owner, shortUrl, disabled)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, bool) domain.Url); ok {
+		r0 = rf(ctx, owner, shortUrl, disabled)
+	} else {
+		r0 = ret.Get(0).(domain.Url)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, bool) error); ok {
+		r1 = rf(ctx, owner, shortUrl, disabled)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateUrl provides a mock function with given fields: ctx, owner, shortUrl, update
+func (_m *URL) UpdateUrl(ctx context.Context, owner string, shortUrl string, update domain.UrlUpdate) (domain.Url, error) {
+	ret := _m.Called(ctx, owner, shortUrl, update)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateUrl")
+	}
+
+	var r0 domain.Url
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, domain.UrlUpdate) (domain.Url, error)); ok {
+		return rf(ctx, owner, shortUrl, update)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, domain.UrlUpdate) domain.Url); ok {
+		r0 = rf(ctx, owner, shortUrl, update)
+	} else {
+		r0 = ret.Get(0).(domain.Url)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, domain.UrlUpdate) error); ok {
+		r1 = rf(ctx, owner, shortUrl, update)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewURL creates a new instance of URL. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewURL(t interface {
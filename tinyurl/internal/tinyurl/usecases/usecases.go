@@ -8,7 +8,30 @@ import (
 )
 
 type URL interface {
-	CreateShortenUrl(ctx context.Context, url string, expiration time.Time) (domain.Url, error)
+	// CreateShortenUrl returns false when an existing url is given back instead of a new one
+	CreateShortenUrl(ctx context.Context, owner string, url string, alias string, expiration time.Time) (domain.Url, bool, error)
 	GetOriginalUrl(ctx context.Context, shortUrl string) (string, error)
 	GetURLMetadata(ctx context.Context, url string) (domain.Url, error)
+	// UpdateUrl changes the fields of the update that are set, only the owner of the url can change it
+	UpdateUrl(ctx context.Context, owner string, shortUrl string, update domain.UrlUpdate) (domain.Url, error)
+	// SetUrlDisabled disables or enables the redirect of the url, only the owner of the url can change it
+	SetUrlDisabled(ctx context.Context, owner string, shortUrl string, disabled bool) (domain.Url, error)
+	DeleteUrl(ctx context.Context, owner string, shortUrl string) error
+	ListUrls(ctx context.Context, owner string) ([]domain.Url, error)
+}
+
+// Users : Authentication of the callers
+type Users interface {
+	Authenticate(ctx context.Context, email string, password string) (bool, error)
+}
+
+// Clicks : Analytics of the redirects
+type Clicks interface {
+	// RecordClick must not slow the redirect down
+	RecordClick(event domain.ClickEvent)
+}
+
+// Stats : Analytics of the clicks of a shorten URL
+type Stats interface {
+	GetURLStats(ctx context.Context, owner string, shortUrl string, from time.Time, to time.Time, bucket domain.StatsBucket) (domain.ClickStats, error)
 }
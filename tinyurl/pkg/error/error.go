@@ -1,6 +1,7 @@
 package error
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 )
@@ -44,6 +45,16 @@ func (e *Error) Is(target error) bool {
 	return reflect.DeepEqual(e, targetError)
 }
 
+// HasCode : Check the code of an error without comparing its message
+func HasCode(err error, code Code) bool {
+	var tinyErr *Error
+	if !errors.As(err, &tinyErr) {
+		return false
+	}
+
+	return tinyErr.Code == code
+}
+
 func NewErrorFromDomain(err error) *Error {
 	if err == nil {
 		return nil
@@ -58,12 +69,14 @@ func NewErrorFromDomain(err error) *Error {
 }
 
 const (
-	OK               Code = 0
-	InvalidArgument  Code = 1
-	NotFound         Code = 2
-	AlreadyExists    Code = 3
-	PermissionDenied Code = 4
-	Unauthenticated  Code = 5
-	DeadlineExceeded Code = 6
-	Internal         Code = 7
+	OK                Code = 0
+	InvalidArgument   Code = 1
+	NotFound          Code = 2
+	AlreadyExists     Code = 3
+	PermissionDenied  Code = 4
+	Unauthenticated   Code = 5
+	DeadlineExceeded  Code = 6
+	Internal          Code = 7
+	Gone              Code = 8
+	ResourceExhausted Code = 9
 )
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"os"
 	"strings"
 	"sync"
 
@@ -43,6 +44,8 @@ func postAndReadURL(sync *sync.WaitGroup, client *http.Client) error {
 	}
 
 	req.Header.Add("Content-Type", "application/json")
+	// Links are created by an existing user
+	req.SetBasicAuth(os.Getenv("LOAD_EMAIL"), os.Getenv("LOAD_PASSWORD"))
 	resp, err := client.Do(req)
 	if err != nil {
 		return err
@@ -0,0 +1,231 @@
+package tests
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	ratelimit "github.com/christapa/testContainers/redis/ratelimiter"
+	tinyHttp "github.com/christapa/tinyurl/internal/tinyurl/api/http"
+	"github.com/christapa/tinyurl/internal/tinyurl/domain"
+	"github.com/christapa/tinyurl/internal/tinyurl/infra/cache"
+	"github.com/christapa/tinyurl/internal/tinyurl/usecases/mocks"
+	"github.com/labstack/echo/v4"
+	redis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	testcontainers "github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+func initRedisContainer(ctx context.Context) (testcontainers.Container, *redis.Client, error) {
+	req := testcontainers.ContainerRequest{
+		Image:        "docker.io/redis:7",
+		ExposedPorts: []string{"6379/tcp"},
+		WaitingFor:   wait.ForLog("* Ready to accept connections"),
+	}
+
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	natPort, err := container.MappedPort(ctx, "6379")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Network: "tcp",
+		Addr:    fmt.Sprintf("localhost:%d", natPort.Int()),
+	})
+
+	return container, client, nil
+}
+
+// TestRateLimitScenario : Create and redirect are limited separately, per client
+func TestRateLimitScenario(t *testing.T) {
+	ctx := context.Background()
+
+	container, client, err := initRedisContainer(ctx)
+	if err != nil {
+		t.Fatalf("Failed to start redis container: %v", err)
+	}
+	defer container.Terminate(ctx)
+	defer client.Close()
+
+	urlService := mocks.NewURL(t)
+	clicks := mocks.NewClicks(t)
+	stats := mocks.NewStats(t)
+
+	url := domain.Url{ShortenURL: "rGu2aeQO", OriginalURL: "https://www.google.com"}
+	urlService.On("CreateShortenUrl", mock.Anything, "", url.OriginalURL, "", time.Time{}).Return(url, true, nil)
+	urlService.On("GetOriginalUrl", mock.Anything, url.ShortenURL).Return(url.OriginalURL, nil)
+	clicks.On("RecordClick", mock.Anything).Return()
+
+	rules := ratelimit.NewRuleStore(client, "tinyurl:ratelimit:rules", time.Minute)
+	err = rules.Set(ctx, ratelimit.Rule{Match: "abuser", Action: ratelimit.RuleDeny})
+	assert.NoError(t, err)
+
+	createFixedLimiter, err := ratelimit.NewRateLimiter(client, time.Minute, 2)
+	assert.NoError(t, err)
+	createLimiter := ratelimit.NewOverrideLimiter(client, createFixedLimiter, rules)
+	redirectLimiter, err := ratelimit.NewRateLimiter(client, time.Minute, 3)
+	assert.NoError(t, err)
+
+	e := echo.New()
+	e.IPExtractor = echo.ExtractIPDirect()
+	e.Use(tinyHttp.NewRateLimitMiddleware([]tinyHttp.RateLimitRule{
+		tinyHttp.NewRateLimitRule(http.MethodPost, "/create", createLimiter),
+		tinyHttp.NewRateLimitRule(http.MethodGet, "/:slug", redirectLimiter),
+	}, "X-API-Key", tinyHttp.NewAPIKeys([]string{"partner", "abuser"})))
+	tinyHttp.RegisterHandlers(e, tinyHttp.NewHttpHandler(urlService, clicks, stats))
+
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	httpClient := &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	create := func(apiKey string, headers ...string) *http.Response {
+		req, err := http.NewRequest(http.MethodPost, server.URL+"/create", strings.NewReader(`{"originalUrl":"https://www.google.com"}`))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		if apiKey != "" {
+			req.Header.Set("X-API-Key", apiKey)
+		}
+		for i := 0; i+1 < len(headers); i += 2 {
+			req.Header.Set(headers[i], headers[i+1])
+		}
+
+		resp, err := httpClient.Do(req)
+		assert.NoError(t, err)
+		resp.Body.Close()
+		return resp
+	}
+
+	t.Run("Create is limited", func(t *testing.T) {
+		for i := range 2 {
+			resp := create("")
+			assert.Equal(t, http.StatusCreated, resp.StatusCode)
+			assert.Equal(t, "2", resp.Header.Get("RateLimit-Limit"))
+			assert.Equal(t, fmt.Sprint(1-i), resp.Header.Get("RateLimit-Remaining"))
+		}
+
+		resp := create("")
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		assert.Equal(t, "60", resp.Header.Get("Retry-After"))
+		assert.Equal(t, "60", resp.Header.Get("RateLimit-Reset"))
+		assert.Equal(t, "0", resp.Header.Get("RateLimit-Remaining"))
+	})
+
+	t.Run("API keys have their own limit", func(t *testing.T) {
+		resp := create("partner")
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	})
+
+	t.Run("Unknown API keys are counted by IP", func(t *testing.T) {
+		resp := create("forged")
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	})
+
+	t.Run("Forwarded IPs of untrusted peers are ignored", func(t *testing.T) {
+		resp := create("", echo.HeaderXForwardedFor, "203.0.113.42", echo.HeaderXRealIP, "203.0.113.43")
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	})
+
+	t.Run("Denied API keys are forbidden", func(t *testing.T) {
+		resp := create("abuser")
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("Redirect has its own limit", func(t *testing.T) {
+		for range 3 {
+			resp, err := httpClient.Get(server.URL + "/" + url.ShortenURL)
+			assert.NoError(t, err)
+			resp.Body.Close()
+			assert.Equal(t, http.StatusFound, resp.StatusCode)
+		}
+
+		resp, err := httpClient.Get(server.URL + "/" + url.ShortenURL)
+		assert.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		assert.NotEmpty(t, resp.Header.Get("Retry-After"))
+	})
+
+	t.Run("Key names do not hold API keys", func(t *testing.T) {
+		keys, err := client.Keys(ctx, "*").Result()
+		assert.NoError(t, err)
+		assert.Contains(t, keys, "tinyurl:ratelimit:POST /create:key:"+sha256Hex("partner"))
+		for _, key := range keys {
+			assert.NotContains(t, key, "partner")
+			assert.NotContains(t, key, "abuser")
+		}
+	})
+}
+
+func sha256Hex(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+// TestAuthFailureScenario : Too many failed authentications block the IP before the password is checked
+func TestAuthFailureScenario(t *testing.T) {
+	ctx := context.Background()
+
+	container, client, err := initRedisContainer(ctx)
+	if err != nil {
+		t.Fatalf("Failed to start redis container: %v", err)
+	}
+	defer container.Terminate(ctx)
+	defer client.Close()
+
+	urlService := mocks.NewURL(t)
+	users := mocks.NewUsers(t)
+
+	users.On("Authenticate", mock.Anything, "test@test.com", "wrong").Return(false, nil)
+
+	e := echo.New()
+	e.IPExtractor = echo.ExtractIPDirect()
+	e.Use(tinyHttp.NewAuthMiddleware(users, cache.NewAuthFailureRedisCounter(client, 3, time.Minute)))
+	tinyHttp.RegisterHandlers(e, tinyHttp.NewHttpHandler(urlService, nil, nil))
+
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	create := func(password string) *http.Response {
+		req, err := http.NewRequest(http.MethodPost, server.URL+"/create", strings.NewReader(`{"originalUrl":"https://www.google.com"}`))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.SetBasicAuth("test@test.com", password)
+
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		resp.Body.Close()
+		return resp
+	}
+
+	for range 3 {
+		resp := create("wrong")
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	}
+
+	// Blocked even with the right password, which is not checked
+	resp := create("right")
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "60", resp.Header.Get("Retry-After"))
+	users.AssertNumberOfCalls(t, "Authenticate", 3)
+}